@@ -1,22 +1,48 @@
 package config
 
 import (
+	"cmp"
+	"fmt"
 	"log"
-	"task_manager_go/model"
+	"strings"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
 )
 
-// InitDB initializes and returns a database connection.
-// Sets up PostgreSQL connection with the specified configuration.
-// Performs database migration for the Task model.
-// Returns a GORM database instance or terminates the application on error.
-func InitDB() *gorm.DB {
+// Database drivers supported by Open.
+const (
+	// DriverPostgres connects to a PostgreSQL server
+	DriverPostgres = "postgres"
+	// DriverSQLite opens an embedded SQLite database file, implemented in pure Go without cgo
+	DriverSQLite = "sqlite"
+)
+
+// DefaultPostgresDSN is the PostgreSQL server connected to when no data source name is given.
+const DefaultPostgresDSN = "host=localhost port=5432 user=alex password=alex dbname=taskdb sslmode=disable"
 
-	connStr := "host=localhost port=5432 user=alex password=alex dbname=taskdb sslmode=disable"
+// DefaultSQLiteDSN is the SQLite database file opened when no data source name is given.
+const DefaultSQLiteDSN = "taskdb.sqlite"
 
-	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+// sqlitePragmas are applied to every SQLite connection unless the data source name sets pragmas itself.
+// They enforce foreign keys like PostgreSQL, let readers work alongside a writer, wait for locks
+// instead of failing at once and take the write lock when a transaction begins, so that two
+// transactions cannot deadlock upgrading their read locks.
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"
+
+// InitDB initializes and returns a database connection.
+// Sets up a connection pool with the database settings of cfg, logging statements at its log level.
+// Performs database migration of the schema and legacy data.
+// Returns a GORM database instance or terminates the application on error.
+func InitDB(cfg Config) *gorm.DB {
+	db, err := open(cfg.Database.Driver, cfg.Database.DataSourceName(), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(gormLogLevel(cfg.Log.Level)),
+	})
 	if err != nil {
 		log.Fatalf("connecting is aborted %v", err)
 	}
@@ -25,6 +51,10 @@ func InitDB() *gorm.DB {
 	if err != nil {
 		log.Fatalf("creating db is uncompleted %v", err)
 	}
+	sqlDb.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDb.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDb.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	sqlDb.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
 
 	err = sqlDb.Ping()
 	if err != nil {
@@ -32,10 +62,87 @@ func InitDB() *gorm.DB {
 	}
 	log.Println("Database was connected")
 
-	err = db.AutoMigrate(&model.Task{})
+	err = Migrate(db)
 	if err != nil {
 		log.Fatalf("Error migrating database: %v", err)
 	}
 
 	return db
 }
+
+// Open connects to the database of the given driver, DriverPostgres when driver is empty.
+// An empty dsn selects the default data source of the driver.
+// Returns a GORM database instance or an error for an unknown driver or a failed connection.
+func Open(driver, dsn string) (*gorm.DB, error) {
+	return open(driver, dsn, &gorm.Config{TranslateError: true})
+}
+
+// open connects to the database of the given driver with the given GORM settings.
+func open(driver, dsn string, gormConfig *gorm.Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case "", DriverPostgres:
+		dialector = postgres.Open(cmp.Or(dsn, DefaultPostgresDSN))
+	case DriverSQLite:
+		dialector = sqliteDialector{&sqlite.Dialector{DSN: sqliteDSN(cmp.Or(dsn, DefaultSQLiteDSN))}}
+	default:
+		return nil, fmt.Errorf("unknown database driver %q, expected %s or %s", driver, DriverPostgres, DriverSQLite)
+	}
+	return gorm.Open(dialector, gormConfig)
+}
+
+// gormLogLevel returns the level of the GORM logger for a log level of LogConfig.
+// GORM logs slow statements as warnings, so info shows them too, and only debug shows every statement.
+func gormLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "debug":
+		return logger.Info
+	case "error":
+		return logger.Error
+	default:
+		return logger.Warn
+	}
+}
+
+// sqliteDSN adds sqlitePragmas to a SQLite data source name that does not set pragmas itself.
+func sqliteDSN(dsn string) string {
+	if strings.Contains(dsn, "_pragma=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&" + sqlitePragmas
+	}
+	return dsn + "?" + sqlitePragmas
+}
+
+// sqliteDialector stores every time in UTC.
+// SQLite keeps times as text, so times are only compared and sorted correctly when they share an offset.
+type sqliteDialector struct {
+	*sqlite.Dialector
+}
+
+// BindVarTo converts the time just added to the statement to UTC before binding it.
+func (d sqliteDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v any) {
+	if last := len(stmt.Vars) - 1; last >= 0 {
+		stmt.Vars[last] = utc(stmt.Vars[last])
+	}
+	d.Dialector.BindVarTo(writer, stmt, v)
+}
+
+// utc returns value in UTC if it is a time and value itself otherwise.
+func utc(value any) any {
+	switch t := value.(type) {
+	case time.Time:
+		return t.UTC()
+	case *time.Time:
+		if t != nil {
+			return t.UTC()
+		}
+	case gorm.DeletedAt:
+		if t.Valid {
+			t.Time = t.Time.UTC()
+			return t
+		}
+	}
+	return value
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"task_manager_go/metrics"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// queryStartKey is the key of the start time of a statement in the instance of a GORM statement.
+const queryStartKey = "metrics:query_start"
+
+// InstrumentDB records the duration of every GORM statement in registry by operation and table,
+// and exposes the statistics of the connection pool of db, read on every scrape.
+// Returns an error if the callbacks cannot be registered or db has no connection pool.
+func InstrumentDB(db *gorm.DB, registry *metrics.Registry) error {
+	sqlDb, err := db.DB()
+	if err != nil {
+		return err
+	}
+	durations := registry.Histogram("db_query_duration_seconds",
+		"Duration of database statements in seconds by operation and table.", metrics.DefaultBuckets, "operation", "table")
+	start := func(db *gorm.DB) {
+		db.InstanceSet(queryStartKey, time.Now())
+	}
+	observe := func(operation string) func(db *gorm.DB) {
+		return func(db *gorm.DB) {
+			if started, ok := db.InstanceGet(queryStartKey); ok {
+				durations.Observe(time.Since(started.(time.Time)).Seconds(), operation, db.Statement.Table)
+			}
+		}
+	}
+	callbacks := db.Callback()
+	err = errors.Join(
+		callbacks.Create().Before("*").Register("metrics:before_create", start),
+		callbacks.Create().After("*").Register("metrics:after_create", observe("create")),
+		callbacks.Query().Before("*").Register("metrics:before_query", start),
+		callbacks.Query().After("*").Register("metrics:after_query", observe("query")),
+		callbacks.Update().Before("*").Register("metrics:before_update", start),
+		callbacks.Update().After("*").Register("metrics:after_update", observe("update")),
+		callbacks.Delete().Before("*").Register("metrics:before_delete", start),
+		callbacks.Delete().After("*").Register("metrics:after_delete", observe("delete")),
+		callbacks.Row().Before("*").Register("metrics:before_row", start),
+		callbacks.Row().After("*").Register("metrics:after_row", observe("row")),
+		callbacks.Raw().Before("*").Register("metrics:before_raw", start),
+		callbacks.Raw().After("*").Register("metrics:after_raw", observe("raw")),
+	)
+	if err != nil {
+		return err
+	}
+
+	registry.GaugeFunc("db_connections", "Number of database connections by state.", []string{"state"},
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			stats := sqlDb.Stats()
+			return []metrics.Sample{
+				{LabelValues: []string{"in_use"}, Value: float64(stats.InUse)},
+				{LabelValues: []string{"idle"}, Value: float64(stats.Idle)},
+			}, nil
+		})
+	registry.GaugeFunc("db_connections_max_open", "Most open database connections, 0 for no limit.", nil,
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			return []metrics.Sample{{Value: float64(sqlDb.Stats().MaxOpenConnections)}}, nil
+		})
+	registry.CounterFunc("db_connection_waits_total", "Number of times a statement waited for a free database connection.", nil,
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			return []metrics.Sample{{Value: float64(sqlDb.Stats().WaitCount)}}, nil
+		})
+	registry.CounterFunc("db_connection_wait_seconds_total", "Total time statements waited for a free database connection in seconds.", nil,
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			return []metrics.Sample{{Value: sqlDb.Stats().WaitDuration.Seconds()}}, nil
+		})
+	registry.CounterFunc("db_connections_closed_total", "Number of database connections closed by the pool by reason.", []string{"reason"},
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			stats := sqlDb.Stats()
+			return []metrics.Sample{
+				{LabelValues: []string{"max_idle_conns"}, Value: float64(stats.MaxIdleClosed)},
+				{LabelValues: []string{"max_idle_time"}, Value: float64(stats.MaxIdleTimeClosed)},
+				{LabelValues: []string{"max_lifetime"}, Value: float64(stats.MaxLifetimeClosed)},
+			}, nil
+		})
+	return nil
+}
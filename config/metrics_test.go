@@ -0,0 +1,33 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"task_manager_go/metrics"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentDB(t *testing.T) {
+	db, err := Open(DriverSQLite, filepath.Join(t.TempDir(), "test.sqlite"))
+	assert.NoError(t, err)
+	sqlDb, err := db.DB()
+	assert.NoError(t, err)
+	defer sqlDb.Close()
+	assert.NoError(t, Migrate(db))
+
+	registry := metrics.NewRegistry()
+	assert.NoError(t, InstrumentDB(db, registry))
+	assert.NoError(t, db.WithContext(t.Context()).Create(&model.Task{Name: "Task", Status: model.StatusNew}).Error)
+	var tasks []model.Task
+	assert.NoError(t, db.WithContext(t.Context()).Find(&tasks).Error)
+
+	var out strings.Builder
+	assert.NoError(t, registry.Write(t.Context(), &out))
+	assert.Contains(t, out.String(), `db_query_duration_seconds_count{operation="create",table="tasks"} 1`+"\n")
+	assert.Contains(t, out.String(), `db_query_duration_seconds_count{operation="query",table="tasks"} 1`+"\n")
+	assert.Contains(t, out.String(), `db_connections{state="idle"} `)
+	assert.Contains(t, out.String(), `db_connections_closed_total{reason="max_lifetime"} 0`+"\n")
+}
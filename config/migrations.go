@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"task_manager_go/model"
+
+	"gorm.io/gorm"
+)
+
+// migratedModels are the models whose tables Migrate creates.
+var migratedModels = []any{&model.Task{}, &model.TaskDependency{}, &model.Tag{}, &model.Comment{}, &model.ChecklistItem{}, &model.AuditEntry{}, &model.TaskEvent{}}
+
+// Migrate brings the database schema and data up to date.
+// Creates or alters the tables of the models and normalizes legacy data.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(migratedModels...); err != nil {
+		return err
+	}
+	if err := backfillTaskTimestamps(db); err != nil {
+		return err
+	}
+	return normalizeTaskStatuses(db)
+}
+
+// CheckMigrations checks that the database has every table Migrate creates with all of its columns,
+// so that a schema migrated by an older version, which lacks newer columns such as version or deleted_at, fails too.
+// Returns an error naming the first missing table or column, or the error of the query.
+func CheckMigrations(ctx context.Context, db *gorm.DB) error {
+	migrator := db.WithContext(ctx).Migrator()
+	for _, m := range migratedModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return err
+		}
+		if !migrator.HasTable(m) {
+			return missingSchema(ctx, "table "+stmt.Schema.Table)
+		}
+		for _, column := range stmt.Schema.DBNames {
+			if !migrator.HasColumn(m, column) {
+				return missingSchema(ctx, fmt.Sprintf("column %s.%s", stmt.Schema.Table, column))
+			}
+		}
+	}
+	return nil
+}
+
+// missingSchema returns the error of a check that did not find part of the schema:
+// the error of ctx if the check was cut short, since the part may exist, or an error naming the part.
+func missingSchema(ctx context.Context, part string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%s is missing, the schema is not migrated", part)
+}
+
+// backfillTaskTimestamps fills the timestamps of rows stored before they were tracked
+// with the task date, which was the best record of when a task was created.
+func backfillTaskTimestamps(db *gorm.DB) error {
+	for _, column := range []string{"created_at", "updated_at"} {
+		err := db.Model(&model.Task{}).Where(column+" IS NULL").UpdateColumn(column, gorm.Expr("date")).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeTaskStatuses rewrites free-form statuses stored before statuses were enforced.
+// Recognized values such as "pending" or "Completed" are mapped to their status,
+// anything else is reset to New so that every row takes part in the workflow.
+func normalizeTaskStatuses(db *gorm.DB) error {
+	err := db.Model(&model.Task{}).Where("status IS NULL OR status = ''").UpdateColumn("status", model.StatusNew).Error
+	if err != nil {
+		return err
+	}
+
+	var values []string
+	if err := db.Model(&model.Task{}).Distinct().Pluck("status", &values).Error; err != nil {
+		return err
+	}
+	for _, value := range values {
+		if model.Status(value).IsValid() {
+			continue
+		}
+		status, ok := model.NormalizeStatus(value)
+		if !ok {
+			log.Printf("Unknown task status %q was reset to %s", value, model.StatusNew)
+			status = model.StatusNew
+		}
+		if err := db.Model(&model.Task{}).Where("status = ?", value).UpdateColumn("status", status).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"path/filepath"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMigrations(t *testing.T) {
+	db, err := Open(DriverSQLite, filepath.Join(t.TempDir(), "test.sqlite"))
+	assert.NoError(t, err)
+	sqlDb, err := db.DB()
+	assert.NoError(t, err)
+	defer sqlDb.Close()
+
+	assert.ErrorContains(t, CheckMigrations(t.Context(), db), "table tasks is missing")
+
+	assert.NoError(t, Migrate(db))
+	assert.NoError(t, CheckMigrations(t.Context(), db))
+
+	assert.NoError(t, db.Migrator().DropColumn(&model.Task{}, "version"))
+	assert.ErrorContains(t, CheckMigrations(t.Context(), db), "column tasks.version is missing")
+}
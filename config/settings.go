@@ -0,0 +1,374 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Stores the server can keep its data in.
+const (
+	// StoreDatabase keeps everything in the database
+	StoreDatabase = "database"
+	// StoreEvents keeps everything in the database and the changes of tasks as events
+	StoreEvents = "events"
+	// StoreMemory keeps everything in memory
+	StoreMemory = "memory"
+	// StoreFile keeps everything in a local file, served from memory
+	StoreFile = "file"
+)
+
+// Log levels of the database logger.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// redacted replaces secrets in printed configurations.
+const redacted = "REDACTED"
+
+// Config holds the settings of the server.
+// Load fills it from, in increasing precedence, Default, a YAML file, TASK_* environment variables
+// and command-line flags.
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Store    StoreConfig    `yaml:"store"`
+	Tasks    TaskConfig     `yaml:"tasks"`
+	Log      LogConfig      `yaml:"log"`
+	// PrintConfig asks for the configuration to be printed instead of starting the server,
+	// it can only be set with --print-config
+	PrintConfig bool `yaml:"-"`
+}
+
+// ServerConfig holds the settings of the HTTP server.
+type ServerConfig struct {
+	// Address is the host and port the server listens on
+	Address string `yaml:"address"`
+	// QueryTimeout is how long the queries of a single request may take
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// HealthTimeout is how long the readiness probe waits for the checks of the dependencies
+	HealthTimeout time.Duration `yaml:"health_timeout"`
+	// ReadTimeout is how long reading a request, including its body, may take, 0 means no limit
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+	// WriteTimeout is how long handling a request and writing its response may take, 0 means no limit
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	// IdleTimeout is how long a keep-alive connection may wait for the next request, 0 means ReadTimeout
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	// DrainDelay is how long the server keeps accepting requests with failing readiness after a shutdown signal,
+	// so that load balancers stop sending requests before the listener closes
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// DrainTimeout is how long requests in flight at shutdown may take to finish before their connections are closed
+	DrainTimeout time.Duration `yaml:"drain_timeout"`
+}
+
+// DatabaseConfig holds the settings of the database connection.
+// The PostgreSQL data source name is built from its parts unless DSN is set.
+type DatabaseConfig struct {
+	// Driver is DriverPostgres or DriverSQLite
+	Driver string `yaml:"driver"`
+	// DSN is the complete PostgreSQL connection string or the path of the SQLite file, overriding the parts below
+	DSN      string `yaml:"dsn"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+	// MaxOpenConns limits the open connections, 0 means no limit
+	MaxOpenConns int `yaml:"max_open_conns"`
+	// MaxIdleConns limits the connections kept open while idle
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// ConnMaxLifetime closes connections older than this, 0 keeps them forever
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// ConnMaxIdleTime closes connections idle longer than this, 0 keeps them forever
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+}
+
+// StoreConfig selects where the server keeps its data.
+type StoreConfig struct {
+	// Kind is StoreDatabase, StoreEvents, StoreMemory or StoreFile
+	Kind string `yaml:"kind"`
+	// File is the task file of StoreFile
+	File string `yaml:"file"`
+	// MemorySnapshot is the file StoreMemory loads tasks from and saves them to, none if empty
+	MemorySnapshot string `yaml:"memory_snapshot"`
+	// MemorySnapshotInterval is how often StoreMemory saves changed tasks
+	MemorySnapshotInterval time.Duration `yaml:"memory_snapshot_interval"`
+}
+
+// TaskConfig holds the settings of the task service.
+type TaskConfig struct {
+	// ChildDeletePolicy is what happens to the subtasks of a deleted task: cascade, orphan or reject
+	ChildDeletePolicy string `yaml:"child_delete_policy"`
+	// TrashRetention is how long deleted tasks stay in the trash before they are purged
+	TrashRetention time.Duration `yaml:"trash_retention"`
+	// TrashPurgeInterval is how often the trash is checked for tasks to purge
+	TrashPurgeInterval time.Duration `yaml:"trash_purge_interval"`
+}
+
+// LogConfig holds the settings of logging.
+type LogConfig struct {
+	// Level of the database logger: debug logs every statement, info and warn slow statements, error only errors
+	Level string `yaml:"level"`
+}
+
+// Default returns the configuration used for everything that is not set otherwise.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:       "localhost:8080",
+			QueryTimeout:  30 * time.Second,
+			HealthTimeout: 2 * time.Second,
+			ReadTimeout:   15 * time.Second,
+			WriteTimeout:  time.Minute,
+			IdleTimeout:   2 * time.Minute,
+			DrainTimeout:  30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			Host:            "localhost",
+			Port:            5432,
+			User:            "alex",
+			Password:        "alex",
+			Name:            "taskdb",
+			SSLMode:         "disable",
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		Store: StoreConfig{
+			Kind:                   StoreDatabase,
+			File:                   "tasks.jsonl",
+			MemorySnapshotInterval: time.Minute,
+		},
+		Tasks: TaskConfig{
+			ChildDeletePolicy:  "orphan",
+			TrashRetention:     30 * 24 * time.Hour,
+			TrashPurgeInterval: time.Hour,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+	}
+}
+
+// Load returns the configuration given by args, the command-line arguments without the program name,
+// and the environment read with getenv, on top of Default.
+// The YAML file named by --config or TASK_CONFIG is applied first, then every TASK_* variable
+// named after a flag, such as TASK_DB_HOST for --db-host, and finally the flags themselves.
+// Returns flag.ErrHelp if help was asked for, and an error listing every problem if the configuration is invalid.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+	var path string
+	flags := cfg.flagSet()
+	flags.StringVar(&path, "config", "", "YAML configuration `file`, also TASK_CONFIG")
+	flags.BoolVar(&cfg.PrintConfig, "print-config", false, "print the configuration with secrets redacted and exit")
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if path == "" {
+		path = getenv("TASK_CONFIG")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	var envErr error
+	flags.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" || f.Name == "print-config" {
+			return
+		}
+		name := envName(f.Name)
+		if value := getenv(name); value != "" {
+			if err := f.Value.Set(value); err != nil {
+				envErr = errors.Join(envErr, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	})
+	if envErr != nil {
+		return Config{}, envErr
+	}
+	// the flags are parsed again so that they take precedence over the file and the environment
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// envName returns the environment variable setting the same value as the named flag.
+func envName(flagName string) string {
+	return "TASK_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// flagSet returns the command-line flags setting the fields of c, with their current values as defaults.
+func (c *Config) flagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet("task_manager_go", flag.ContinueOnError)
+	flags.StringVar(&c.Server.Address, "address", c.Server.Address, "host and port to listen on")
+	flags.DurationVar(&c.Server.QueryTimeout, "query-timeout", c.Server.QueryTimeout, "how long the queries of a request may take")
+	flags.DurationVar(&c.Server.HealthTimeout, "health-timeout", c.Server.HealthTimeout, "how long the readiness probe waits for its checks")
+	flags.DurationVar(&c.Server.ReadTimeout, "read-timeout", c.Server.ReadTimeout, "how long reading a request may take, 0 for no limit")
+	flags.DurationVar(&c.Server.WriteTimeout, "write-timeout", c.Server.WriteTimeout, "how long answering a request may take, 0 for no limit")
+	flags.DurationVar(&c.Server.IdleTimeout, "idle-timeout", c.Server.IdleTimeout, "how long an idle keep-alive connection stays open")
+	flags.DurationVar(&c.Server.DrainDelay, "drain-delay", c.Server.DrainDelay, "how long requests are still accepted with failing readiness after a shutdown signal")
+	flags.DurationVar(&c.Server.DrainTimeout, "drain-timeout", c.Server.DrainTimeout, "how long requests in flight at shutdown may take to finish")
+
+	flags.StringVar(&c.Database.Driver, "db-driver", c.Database.Driver, "database driver, postgres or sqlite")
+	flags.StringVar(&c.Database.DSN, "db-dsn", c.Database.DSN, "PostgreSQL connection string or SQLite file, overrides the other db settings")
+	flags.StringVar(&c.Database.Host, "db-host", c.Database.Host, "PostgreSQL host")
+	flags.IntVar(&c.Database.Port, "db-port", c.Database.Port, "PostgreSQL port")
+	flags.StringVar(&c.Database.User, "db-user", c.Database.User, "PostgreSQL user")
+	flags.StringVar(&c.Database.Password, "db-password", c.Database.Password, "PostgreSQL password")
+	flags.StringVar(&c.Database.Name, "db-name", c.Database.Name, "PostgreSQL database name")
+	flags.StringVar(&c.Database.SSLMode, "db-sslmode", c.Database.SSLMode, "PostgreSQL sslmode")
+	flags.IntVar(&c.Database.MaxOpenConns, "db-max-open-conns", c.Database.MaxOpenConns, "most open database connections, 0 for no limit")
+	flags.IntVar(&c.Database.MaxIdleConns, "db-max-idle-conns", c.Database.MaxIdleConns, "most idle database connections")
+	flags.DurationVar(&c.Database.ConnMaxLifetime, "db-conn-max-lifetime", c.Database.ConnMaxLifetime, "longest time a database connection is used, 0 for no limit")
+	flags.DurationVar(&c.Database.ConnMaxIdleTime, "db-conn-max-idle-time", c.Database.ConnMaxIdleTime, "longest time a database connection stays idle, 0 for no limit")
+
+	flags.StringVar(&c.Store.Kind, "store", c.Store.Kind, "where data is kept: database, events, memory or file")
+	flags.StringVar(&c.Store.File, "file", c.Store.File, "task file of the file store")
+	flags.StringVar(&c.Store.MemorySnapshot, "memory-snapshot", c.Store.MemorySnapshot, "snapshot file of the memory store")
+	flags.DurationVar(&c.Store.MemorySnapshotInterval, "memory-snapshot-interval", c.Store.MemorySnapshotInterval, "how often the memory store saves changed tasks")
+
+	flags.StringVar(&c.Tasks.ChildDeletePolicy, "child-delete-policy", c.Tasks.ChildDeletePolicy, "what happens to the subtasks of a deleted task: cascade, orphan or reject")
+	flags.DurationVar(&c.Tasks.TrashRetention, "trash-retention", c.Tasks.TrashRetention, "how long deleted tasks stay in the trash")
+	flags.DurationVar(&c.Tasks.TrashPurgeInterval, "trash-purge-interval", c.Tasks.TrashPurgeInterval, "how often the trash is purged")
+
+	flags.StringVar(&c.Log.Level, "log-level", c.Log.Level, "log level: debug, info, warn or error")
+	return flags
+}
+
+// loadFile applies the settings of the YAML file at path. Unknown settings are rejected.
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks the configuration and returns an error listing every invalid setting.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	_, _, err := net.SplitHostPort(c.Server.Address)
+	check(err == nil, "server address %q must be host:port", c.Server.Address)
+	check(c.Server.QueryTimeout > 0, "query timeout must be positive")
+	check(c.Server.HealthTimeout > 0, "health timeout must be positive")
+	check(c.Server.ReadTimeout >= 0, "read timeout must not be negative")
+	check(c.Server.WriteTimeout == 0 || c.Server.WriteTimeout > c.Server.QueryTimeout,
+		"write timeout must be longer than the query timeout, so that timed out requests can still be answered")
+	check(c.Server.IdleTimeout >= 0, "idle timeout must not be negative")
+	check(c.Server.DrainDelay >= 0, "drain delay must not be negative")
+	check(c.Server.DrainTimeout > 0, "drain timeout must be positive")
+
+	db := c.Database
+	check(db.Driver == DriverPostgres || db.Driver == DriverSQLite, "database driver must be %s or %s, got %q", DriverPostgres, DriverSQLite, db.Driver)
+	if db.Driver == DriverPostgres && db.DSN == "" {
+		check(db.Host != "", "database host is required")
+		check(db.Port > 0 && db.Port <= 65535, "database port must be between 1 and 65535, got %d", db.Port)
+		check(db.Name != "", "database name is required")
+	}
+	check(db.MaxOpenConns >= 0, "max open connections must not be negative")
+	check(db.MaxIdleConns >= 0, "max idle connections must not be negative")
+	check(db.MaxOpenConns == 0 || db.MaxIdleConns <= db.MaxOpenConns, "max idle connections must not exceed max open connections")
+	check(db.ConnMaxLifetime >= 0, "connection max lifetime must not be negative")
+	check(db.ConnMaxIdleTime >= 0, "connection max idle time must not be negative")
+
+	stores := []string{StoreDatabase, StoreEvents, StoreMemory, StoreFile}
+	check(slices.Contains(stores, c.Store.Kind), "store must be one of %s, got %q", strings.Join(stores, ", "), c.Store.Kind)
+	check(c.Store.Kind != StoreFile || c.Store.File != "", "the file store needs a file")
+	check(c.Store.MemorySnapshotInterval > 0, "memory snapshot interval must be positive")
+
+	check(c.Tasks.TrashRetention > 0, "trash retention must be positive")
+	check(c.Tasks.TrashPurgeInterval > 0, "trash purge interval must be positive")
+	check(slices.Contains(logLevels, c.Log.Level), "log level must be one of %s, got %q", strings.Join(logLevels, ", "), c.Log.Level)
+	return errors.Join(errs...)
+}
+
+// DataSourceName returns the data source name to connect with:
+// DSN if set, the default SQLite file for SQLite and otherwise a PostgreSQL connection string of the parts.
+func (d DatabaseConfig) DataSourceName() string {
+	switch {
+	case d.DSN != "":
+		return d.DSN
+	case d.Driver == DriverSQLite:
+		return DefaultSQLiteDSN
+	default:
+		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			dsnValue(d.Host), d.Port, dsnValue(d.User), dsnValue(d.Password), dsnValue(d.Name), dsnValue(d.SSLMode))
+	}
+}
+
+// dsnValue quotes a value of a PostgreSQL connection string if it is empty or contains spaces or quotes.
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// passwordSetting matches the password of a PostgreSQL connection string in key=value form.
+var passwordSetting = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Redacted returns a copy of the configuration with the database password replaced,
+// also where it is part of the DSN.
+func (c Config) Redacted() Config {
+	if c.Database.Password != "" {
+		c.Database.Password = redacted
+	}
+	c.Database.DSN = redactDSN(c.Database.DSN)
+	return c
+}
+
+// redactDSN replaces the password in a PostgreSQL connection string in URL or key=value form.
+func redactDSN(dsn string) string {
+	if !strings.Contains(dsn, "://") {
+		return passwordSetting.ReplaceAllString(dsn, "${1}"+redacted)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		// an unparsable URL might show the password anywhere
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	if query := u.Query(); query.Has("password") {
+		query.Set("password", redacted)
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// WriteRedacted writes the configuration with secrets redacted as YAML, in the format Load reads.
+func (c Config) WriteRedacted(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+server:
+  address: 0.0.0.0:9000
+database:
+  host: file-host
+  port: 6000
+  user: file-user
+tasks:
+  trash_retention: 48h
+`), 0o644))
+	env := map[string]string{
+		"TASK_CONFIG":  path,
+		"TASK_DB_HOST": "env-host",
+		"TASK_DB_PORT": "7000",
+	}
+
+	cfg, err := Load([]string{"--db-host", "flag-host"}, func(name string) string { return env[name] })
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:9000", cfg.Server.Address)
+	assert.Equal(t, "flag-host", cfg.Database.Host)
+	assert.Equal(t, 7000, cfg.Database.Port)
+	assert.Equal(t, "file-user", cfg.Database.User)
+	assert.Equal(t, "taskdb", cfg.Database.Name)
+	assert.Equal(t, 48*time.Hour, cfg.Tasks.TrashRetention)
+	assert.Equal(t, "host=flag-host port=7000 user=file-user password=alex dbname=taskdb sslmode=disable", cfg.Database.DataSourceName())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	env := map[string]string{"TASK_STORE": "cloud"}
+	_, err := Load([]string{"--address", "nowhere", "--query-timeout", "0s"}, func(name string) string { return env[name] })
+	assert.ErrorContains(t, err, "server address")
+	assert.ErrorContains(t, err, "query timeout")
+	assert.ErrorContains(t, err, "store must be one of")
+
+	env = map[string]string{"TASK_DB_PORT": "many"}
+	_, err = Load(nil, func(name string) string { return env[name] })
+	assert.ErrorContains(t, err, "TASK_DB_PORT")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("database:\n  hots: typo\n"), 0o644))
+	_, err = Load([]string{"--config", path}, func(string) string { return "" })
+	assert.ErrorContains(t, err, "hots")
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "s3cret"
+	cfg.Database.DSN = "host=db user=app password='s3 cret' dbname=tasks"
+	redacted := cfg.Redacted()
+	assert.Equal(t, "REDACTED", redacted.Database.Password)
+	assert.Equal(t, "host=db user=app password=REDACTED dbname=tasks", redacted.Database.DSN)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+
+	cfg.Database.DSN = "postgres://app:s3cret@db:5432/tasks?sslmode=require"
+	assert.Equal(t, "postgres://app:REDACTED@db:5432/tasks?sslmode=require", cfg.Redacted().Database.DSN)
+
+	var out bytes.Buffer
+	assert.NoError(t, cfg.WriteRedacted(&out))
+	assert.NotContains(t, out.String(), "s3cret")
+	assert.Contains(t, out.String(), "query_timeout: 30s")
+
+	path := filepath.Join(t.TempDir(), "printed.yaml")
+	assert.NoError(t, os.WriteFile(path, out.Bytes(), 0o644))
+	loaded, err := Load([]string{"--config", path}, func(string) string { return "" })
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Redacted(), loaded)
+}
+
+func TestDatabaseConfig_DataSourceName(t *testing.T) {
+	db := Default().Database
+	db.Password = `it's secret`
+	db.User = ""
+	assert.Equal(t, `host=localhost port=5432 user='' password='it\'s secret' dbname=taskdb sslmode=disable`, db.DataSourceName())
+
+	db.Driver = DriverSQLite
+	assert.Equal(t, DefaultSQLiteDSN, db.DataSourceName())
+	db.DSN = "/var/lib/tasks.sqlite"
+	assert.Equal(t, "/var/lib/tasks.sqlite", db.DataSourceName())
+}
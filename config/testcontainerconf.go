@@ -3,7 +3,6 @@ package config
 import (
 	"context"
 	"fmt"
-	"task_manager_go/model"
 
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -35,8 +34,8 @@ func InitTestDBWithDocker() (*gorm.DB, func()) {
 	port, _ := postgresContainer.MappedPort(ctx, "5432/tcp")
 
 	dsn := fmt.Sprintf("host=%s port=%s user=test password=test dbname=testdb sslmode=disable", host, port.Port())
-	db, _ := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	err := db.AutoMigrate(&model.Task{})
+	db, _ := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	err := Migrate(db)
 	if err != nil {
 		return nil, nil
 	}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"gorm.io/gorm"
+)
+
+// InitTestDB initializes a migrated database for tests.
+// Uses a SQLite database in a temporary directory, so tests run without Docker,
+// or a PostgreSQL container when the TEST_DB_DRIVER environment variable is set to postgres.
+// Returns a GORM database instance and a cleanup function.
+func InitTestDB() (*gorm.DB, func()) {
+	if os.Getenv("TEST_DB_DRIVER") == DriverPostgres {
+		return InitTestDBWithDocker()
+	}
+
+	dir, err := os.MkdirTemp("", "taskdb")
+	if err != nil {
+		log.Fatalf("creating test database directory: %v", err)
+	}
+	db, err := Open(DriverSQLite, filepath.Join(dir, "test.sqlite"))
+	if err != nil {
+		log.Fatalf("opening test database: %v", err)
+	}
+	if err := Migrate(db); err != nil {
+		log.Fatalf("migrating test database: %v", err)
+	}
+
+	cleanup := func() {
+		if sqlDb, err := db.DB(); err == nil {
+			sqlDb.Close()
+		}
+		os.RemoveAll(dir)
+	}
+
+	return db, cleanup
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"task_manager_go/model"
+	"task_manager_go/service"
+)
+
+// ActorHeader is the request header naming who makes a change, recorded in the audit trail.
+const ActorHeader = "X-Actor"
+
+// anonymousActor is recorded for changes made by requests without an ActorHeader.
+const anonymousActor = "anonymous"
+
+// AuditController handles HTTP requests for the audit trail of tasks.
+type AuditController struct {
+	service *service.AuditService
+}
+
+// NewAuditController creates a new instance of AuditController with the specified service.
+func NewAuditController(service *service.AuditService) *AuditController {
+	return &AuditController{service: service}
+}
+
+// auditListResponse is the JSON body returned by the audit endpoints.
+type auditListResponse struct {
+	Items  []model.AuditEntry `json:"items"`
+	Total  int64              `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+// GetHistory handles GET request to retrieve the changes of a task, most recent first.
+// Expects task ID in the URL path and accepts the same query parameters as FindEntries.
+// Returns a JSON page of audit entries with the total count, 400 for invalid query parameters, or an error response.
+func (c *AuditController) GetHistory(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	query, ok := parseAuditQuery(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := c.service.History(r.Context(), taskId, query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeAuditPage(w, page)
+}
+
+// FindEntries handles GET request to query the audit trail of all tasks, most recent first.
+// Accepts actor, from and to (RFC 3339) filters and limit and offset query parameters.
+// Returns a JSON page of audit entries with the total count, 400 for invalid query parameters, or an error response.
+func (c *AuditController) FindEntries(w http.ResponseWriter, r *http.Request) {
+	query, ok := parseAuditQuery(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := c.service.FindEntries(r.Context(), query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeAuditPage(w, page)
+}
+
+// parseAuditQuery reads the filters and pagination of an audit query from the URL query parameters.
+// Writes a 400 response and returns false when a parameter is invalid.
+func parseAuditQuery(w http.ResponseWriter, r *http.Request) (model.AuditQuery, bool) {
+	values := r.URL.Query()
+	query := model.AuditQuery{Actor: values.Get("actor")}
+	var err error
+	if query.From, err = parseTimeParam(values.Get("from")); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid from, expected an RFC 3339 timestamp")
+		return model.AuditQuery{}, false
+	}
+	if query.To, err = parseTimeParam(values.Get("to")); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid to, expected an RFC 3339 timestamp")
+		return model.AuditQuery{}, false
+	}
+	if query.Limit, err = parseIntParam(values.Get("limit")); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid limit")
+		return model.AuditQuery{}, false
+	}
+	if query.Offset, err = parseIntParam(values.Get("offset")); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid offset")
+		return model.AuditQuery{}, false
+	}
+	return query, true
+}
+
+// writeAuditPage writes a page of audit entries as a JSON response.
+func writeAuditPage(w http.ResponseWriter, page model.AuditPage) {
+	response := auditListResponse{Items: page.Entries, Total: page.Total, Limit: page.Limit, Offset: page.Offset}
+	if page.Entries == nil {
+		response.Items = []model.AuditEntry{}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// requestActor returns who makes the changes requested by r, read from the ActorHeader.
+func requestActor(r *http.Request) string {
+	if actor := strings.TrimSpace(r.Header.Get(ActorHeader)); actor != "" {
+		return actor
+	}
+	return anonymousActor
+}
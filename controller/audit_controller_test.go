@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"task_manager_go/repository"
+	"task_manager_go/service"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditController(t *testing.T) {
+	auditRepo := repository.NewMockAuditRepository()
+	taskController := NewTaskController(service.NewTaskService(repository.NewMockTaskRepository(), service.WithAudit(auditRepo)))
+	auditController := NewAuditController(service.NewAuditService(auditRepo))
+	r := mux.NewRouter()
+	r.HandleFunc("/tasks", taskController.CreateTask).Methods("POST")
+	r.HandleFunc("/tasks/{id}", taskController.UpdateTaskById).Methods("PATCH")
+	r.HandleFunc("/tasks/{id}/history", auditController.GetHistory).Methods("GET")
+	r.HandleFunc("/audit", auditController.FindEntries).Methods("GET")
+
+	assert.Equal(t, http.StatusCreated, serveWithHeader(r, "POST", "/tasks", ActorHeader, "alex", `{"Name":"Task"}`).Code)
+	assert.Equal(t, http.StatusOK, serve(r, "PATCH", "/tasks/1", `{"Name":"Renamed"}`).Code)
+
+	recorder := serve(r, "GET", "/tasks/1/history?limit=1", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var history struct {
+		Items []struct {
+			Actor     string
+			Operation string
+			Changes   []map[string]any
+		} `json:"items"`
+		Total int64 `json:"total"`
+		Limit int   `json:"limit"`
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&history))
+	assert.Equal(t, int64(2), history.Total)
+	assert.Equal(t, 1, history.Limit)
+	assert.Len(t, history.Items, 1)
+	assert.Equal(t, anonymousActor, history.Items[0].Actor)
+	assert.Equal(t, "update", history.Items[0].Operation)
+	assert.Equal(t, []map[string]any{{"Field": "Name", "Before": "Task", "After": "Renamed"}}, history.Items[0].Changes)
+
+	recorder = serve(r, "GET", "/tasks/1/history?actor=alex", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&history))
+	assert.Equal(t, int64(1), history.Total)
+	assert.Equal(t, "create", history.Items[0].Operation)
+	assert.Equal(t, http.StatusBadRequest, serve(r, "GET", "/tasks/1/history?to=tomorrow", "").Code)
+
+	recorder = serve(r, "GET", "/audit?actor=alex&from=2000-01-01T00:00:00Z", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&history))
+	assert.Equal(t, int64(1), history.Total)
+	assert.Equal(t, "create", history.Items[0].Operation)
+
+	assert.Equal(t, http.StatusBadRequest, serve(r, "GET", "/audit?from=yesterday", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(r, "GET", "/audit?from=2030-01-01T00:00:00Z&to=2000-01-01T00:00:00Z", "").Code)
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"net/http"
+	"task_manager_go/service"
+)
+
+// ChecklistController handles HTTP requests for the checklists embedded in tasks.
+type ChecklistController struct {
+	service *service.ChecklistService
+}
+
+// NewChecklistController creates a new instance of ChecklistController with the specified service.
+func NewChecklistController(service *service.ChecklistService) *ChecklistController {
+	return &ChecklistController{service: service}
+}
+
+// checklistItemRequest is the JSON body of POST /tasks/{id}/checklist.
+type checklistItemRequest struct {
+	Text string
+}
+
+// reorderRequest is the JSON body of PUT /tasks/{id}/checklist/order.
+type reorderRequest struct {
+	// ItemIds lists every item of the checklist in the new order
+	ItemIds []uint
+}
+
+// GetChecklist handles GET request to retrieve the checklist of a task.
+// Expects task ID in the URL path.
+// Returns the items in order with the completion percentage or an error response.
+func (c *ChecklistController) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	checklist, err := c.service.GetChecklist(r.Context(), taskId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, checklist)
+}
+
+// AddItem handles POST request to append an item to the checklist of a task.
+// Expects task ID in the URL path and the item text in JSON format in the request body.
+// Returns the created item or an error response.
+func (c *ChecklistController) AddItem(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	var request checklistItemRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	item, err := c.service.AddItem(r.Context(), taskId, request.Text)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, item)
+}
+
+// ReorderItems handles PUT request to put the checklist of a task into a new order.
+// Expects task ID in the URL path and every item ID in the new order in JSON format in the request body.
+// Returns the reordered checklist or an error response.
+func (c *ChecklistController) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	var request reorderRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	checklist, err := c.service.Reorder(r.Context(), taskId, request.ItemIds)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, checklist)
+}
+
+// ToggleItem handles POST request to flip the done flag of a checklist item.
+// Expects task ID and item ID in the URL path.
+// Returns the updated item or an error response.
+func (c *ChecklistController) ToggleItem(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	itemId, ok := pathId(w, r, "itemId", "checklist item")
+	if !ok {
+		return
+	}
+	item, err := c.service.ToggleItem(r.Context(), taskId, itemId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+// DeleteItem handles DELETE request to remove an item from the checklist of a task.
+// Expects task ID and item ID in the URL path.
+// Returns success or error response.
+func (c *ChecklistController) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	itemId, ok := pathId(w, r, "itemId", "checklist item")
+	if !ok {
+		return
+	}
+	if err := c.service.DeleteItem(r.Context(), taskId, itemId); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"net/http"
+	"task_manager_go/model"
+	"task_manager_go/service"
+)
+
+// CommentController handles HTTP requests for the comment threads of tasks.
+type CommentController struct {
+	service *service.CommentService
+}
+
+// NewCommentController creates a new instance of CommentController with the specified service.
+func NewCommentController(service *service.CommentService) *CommentController {
+	return &CommentController{service: service}
+}
+
+// commentRequest is the JSON body for posting and editing a comment.
+// The author is the actor of the request and cannot be set in the body.
+type commentRequest struct {
+	Body string
+}
+
+// commentListResponse is the JSON body returned by GET /tasks/{id}/comments.
+type commentListResponse struct {
+	Items  []model.Comment `json:"items"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+// ListComments handles GET request to retrieve the comments of a task, oldest first.
+// Expects task ID in the URL path and accepts limit and offset query parameters.
+// Returns a JSON page of comments with the total count or an error response.
+func (c *CommentController) ListComments(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	offset, err := parseIntParam(r.URL.Query().Get("offset"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	page, err := c.service.ListComments(r.Context(), taskId, limit, offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := commentListResponse{Items: page.Comments, Total: page.Total, Limit: page.Limit, Offset: page.Offset}
+	if page.Comments == nil {
+		response.Items = []model.Comment{}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// AddComment handles POST request to post a comment on a task.
+// Expects task ID in the URL path and the body in JSON format in the request body; the author is taken from the ActorHeader.
+// Returns the created comment or an error response.
+func (c *CommentController) AddComment(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	var request commentRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	comment, err := c.service.AddComment(r.Context(), taskId, requestActor(r), request.Body)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// EditComment handles PATCH request to change the body of a comment.
+// Expects task ID and comment ID in the URL path and the new body in JSON format in the request body.
+// Returns the updated comment or an error response.
+func (c *CommentController) EditComment(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	commentId, ok := pathId(w, r, "commentId", "comment")
+	if !ok {
+		return
+	}
+	var request commentRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+
+	comment, err := c.service.EditComment(r.Context(), taskId, commentId, request.Body)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE request to remove a comment.
+// Expects task ID and comment ID in the URL path.
+// Returns success or error response.
+func (c *CommentController) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	taskId, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	commentId, ok := pathId(w, r, "commentId", "comment")
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteComment(r.Context(), taskId, commentId); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
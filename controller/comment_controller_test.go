@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"task_manager_go/service"
+	"task_manager_go/validation"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentController(t *testing.T) {
+	tasks := repository.NewMockTaskRepository()
+	commentController := NewCommentController(service.NewCommentService(repository.NewMockCommentRepository(), tasks))
+	r := mux.NewRouter()
+	r.HandleFunc("/tasks/{id}/comments", commentController.AddComment).Methods("POST")
+	r.HandleFunc("/tasks/{id}/comments/{commentId}", commentController.EditComment).Methods("PATCH")
+	task, err := tasks.CreateTask(t.Context(), model.Task{Name: "Task", Status: model.StatusNew})
+	assert.NoError(t, err)
+
+	recorder := serveWithHeader(r, "POST", "/tasks/1/comments", ActorHeader, "alex", `{"Body":"Looks good"}`)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	var comment model.Comment
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&comment))
+	assert.Equal(t, task.Id, comment.TaskId)
+	assert.Equal(t, "alex", comment.Author)
+
+	recorder = serve(r, "POST", "/tasks/1/comments", `{"Body":"Anonymous"}`)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&comment))
+	assert.Equal(t, anonymousActor, comment.Author)
+
+	recorder = serve(r, "PATCH", "/tasks/1/comments/1", `{"Author":"maria","Body":"Changed"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	var p problem
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&p))
+	assert.Equal(t, []validation.FieldError{{Field: "Author", Message: "is not a field that can be set"}}, p.Errors)
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(r, "POST", "/tasks/1/comments", `{"Author":"maria","Body":"Hi"}`).Code)
+
+	recorder = serve(r, "PATCH", "/tasks/1/comments/1", `{"Body":"Changed"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&comment))
+	assert.Equal(t, "alex", comment.Author)
+	assert.True(t, comment.Edited)
+}
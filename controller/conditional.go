@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager_go/model"
+)
+
+// taskETag returns the entity tag of a task, derived from its version,
+// which changes with the task, its checklist and its tags, everything GET /tasks/{id} returns.
+func taskETag(task model.Task) string {
+	return strconv.Quote(strconv.FormatUint(uint64(task.Version), 10))
+}
+
+// etagMatches reports whether header, the value of an If-Match or If-None-Match header, is "*" or lists etag.
+// Weak entity tags only match when weak is true, If-Match requires the strong comparison of RFC 9110.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified answers a conditional GET whose If-None-Match header matches the task with 304 and reports whether it did.
+func notModified(w http.ResponseWriter, r *http.Request, task model.Task) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" || !etagMatches(header, taskETag(task), true) {
+		return false
+	}
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// preconditionVersion evaluates the If-Match header of r against the current state of a task.
+// Returns the version a change must be based on, 0 if the request has no If-Match header or uses "*".
+// Writes a 412 response carrying the current ETag and returns false if the header does not match.
+func preconditionVersion(w http.ResponseWriter, r *http.Request, current model.Task) (uint, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return 0, true
+	}
+	if !etagMatches(header, taskETag(current), false) {
+		w.Header().Set("ETag", taskETag(current))
+		writeProblem(w, r, http.StatusPreconditionFailed, "The task has been changed, fetch it again to get its current ETag")
+		return 0, false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return 0, true
+	}
+	return current.Version, true
+}
+
+// ifMatchVersion reads the task with the given id if the request has an If-Match header and evaluates it,
+// see preconditionVersion. Writes an error response and returns false if the task cannot be read.
+func (c *TaskController) ifMatchVersion(w http.ResponseWriter, r *http.Request, id uint) (uint, bool) {
+	if r.Header.Get("If-Match") == "" {
+		return 0, true
+	}
+	current, err := c.service.GetTaskByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return 0, false
+	}
+	return preconditionVersion(w, r, current)
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HealthChecker checks a dependency the server needs to take requests, such as its database.
+type HealthChecker interface {
+	// Name identifies the dependency in the response of the readiness probe
+	Name() string
+	// Check returns an error when the dependency cannot be used, giving up when ctx is done
+	Check(ctx context.Context) error
+}
+
+// HealthCheck returns a HealthChecker with the given name that runs check.
+func HealthCheck(name string, check func(ctx context.Context) error) HealthChecker {
+	return healthCheck{name: name, check: check}
+}
+
+// healthCheck is the HealthChecker returned by HealthCheck.
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// Name returns the name the check was created with.
+func (c healthCheck) Name() string {
+	return c.name
+}
+
+// Check runs the function the check was created with.
+func (c healthCheck) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+// HealthController handles the probes load balancers and orchestrators use to decide
+// whether to restart the server and whether to send requests to it.
+type HealthController struct {
+	timeout  time.Duration
+	checkers []HealthChecker
+	draining atomic.Bool
+}
+
+// NewHealthController creates a new instance of HealthController for a server that is ready
+// once the given checkers pass within timeout.
+func NewHealthController(timeout time.Duration, checkers ...HealthChecker) *HealthController {
+	return &HealthController{timeout: timeout, checkers: checkers}
+}
+
+// healthResponse is the JSON body of a probe.
+type healthResponse struct {
+	Status string `json:"status"`
+	// Checks maps the name of every checked dependency to ok or the reason it failed
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// versionResponse is the JSON body of the version endpoint.
+type versionResponse struct {
+	Path      string `json:"path"`
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// Register adds checkers that must pass for the server to be ready.
+// Must be called before the server starts.
+func (c *HealthController) Register(checkers ...HealthChecker) {
+	c.checkers = append(c.checkers, checkers...)
+}
+
+// StartDraining makes the readiness probe fail from now on, so that no new requests are sent
+// to a server that is shutting down.
+func (c *HealthController) StartDraining() {
+	c.draining.Store(true)
+}
+
+// Live handles GET request to check whether the server process is alive.
+// Returns 200 with status alive as long as the server answers requests at all.
+func (c *HealthController) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{Status: "alive"})
+}
+
+// Ready handles GET request to check whether the server takes requests.
+// Runs every checker at once, each within the timeout of the controller.
+// Returns 200 with status ready and the result of every check, 503 with status unavailable
+// when a check fails, or 503 with status draining once the server is shutting down.
+func (c *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	if c.draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, healthResponse{Status: "draining"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), c.timeout)
+	defer cancel()
+
+	errs := make([]error, len(c.checkers))
+	var wg sync.WaitGroup
+	for i, checker := range c.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = checker.Check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	response := healthResponse{Status: "ready"}
+	status := http.StatusOK
+	if len(c.checkers) > 0 {
+		response.Checks = make(map[string]string, len(c.checkers))
+	}
+	for i, checker := range c.checkers {
+		if errs[i] != nil {
+			response.Checks[checker.Name()] = errs[i].Error()
+			response.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		} else {
+			response.Checks[checker.Name()] = "ok"
+		}
+	}
+	writeJSON(w, status, response)
+}
+
+// buildVersion reads the build information embedded in the binary once.
+var buildVersion = sync.OnceValue(func() versionResponse {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return versionResponse{Version: "unknown"}
+	}
+	version := versionResponse{
+		Path:      info.Main.Path,
+		Version:   info.Main.Version,
+		GoVersion: info.GoVersion,
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version.Revision = setting.Value
+		case "vcs.time":
+			version.Time = setting.Value
+		case "vcs.modified":
+			version.Modified = setting.Value == "true"
+		}
+	}
+	return version
+})
+
+// Version handles GET request to get the version of the running binary.
+// Returns 200 with the module path and version, the Go version and, when built from
+// a repository, the revision, its time and whether it had uncommitted changes.
+func Version(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, buildVersion())
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthController_Draining(t *testing.T) {
+	health := NewHealthController(time.Second)
+	handler := http.HandlerFunc(health.Ready)
+
+	recorder := serve(handler, "GET", "/readyz", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"ready"}`, recorder.Body.String())
+
+	health.StartDraining()
+	recorder = serve(handler, "GET", "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"status":"draining"}`, recorder.Body.String())
+
+	recorder = serve(http.HandlerFunc(health.Live), "GET", "/healthz", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"alive"}`, recorder.Body.String())
+}
+
+func TestHealthController_Checks(t *testing.T) {
+	health := NewHealthController(50*time.Millisecond, HealthCheck("database", func(ctx context.Context) error {
+		return nil
+	}))
+	handler := http.HandlerFunc(health.Ready)
+
+	recorder := serve(handler, "GET", "/readyz", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"ready","checks":{"database":"ok"}}`, recorder.Body.String())
+
+	health.Register(
+		HealthCheck("cache", func(ctx context.Context) error {
+			return errors.New("connection refused")
+		}),
+		HealthCheck("queue", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	recorder = serve(handler, "GET", "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.JSONEq(t, `{"status":"unavailable","checks":{"database":"ok","cache":"connection refused","queue":"context deadline exceeded"}}`, recorder.Body.String())
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"task_manager_go/metrics"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// unmatchedRoute is the route label of requests that match no route, so that unknown paths
+// do not create a series each.
+const unmatchedRoute = "unmatched"
+
+// MetricsController handles the endpoint Prometheus scrapes the metrics of the server from.
+type MetricsController struct {
+	registry *metrics.Registry
+}
+
+// NewMetricsController creates a new instance of MetricsController exposing the metrics of registry.
+func NewMetricsController(registry *metrics.Registry) *MetricsController {
+	return &MetricsController{registry: registry}
+}
+
+// Metrics handles GET request to get the metrics of the server in the Prometheus text format.
+// Metrics that cannot be read, such as task counts while the database is down, are left out and logged.
+func (c *MetricsController) Metrics(w http.ResponseWriter, r *http.Request) {
+	var body bytes.Buffer
+	if err := c.registry.Write(r.Context(), &body); err != nil {
+		log.Println("Failed to collect metrics:", err)
+	}
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		log.Println("Failed to write response:", err)
+	}
+}
+
+// RequestMetrics returns a middleware that counts requests and records their durations in registry
+// by method, route template and status code, so that /tasks/1 and /tasks/2 share the series of /tasks/{id}.
+// It must wrap the handlers of unmatched requests as well, because mux does not run middlewares for them;
+// their route is "unmatched".
+func RequestMetrics(registry *metrics.Registry) mux.MiddlewareFunc {
+	requests := registry.Counter("http_requests_total",
+		"Number of HTTP requests by method, route and status code.", "method", "route", "status")
+	durations := registry.Histogram("http_request_duration_seconds",
+		"Duration of HTTP requests in seconds by method, route and status code.", metrics.DefaultBuckets, "method", "route", "status")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			route := unmatchedRoute
+			if current := mux.CurrentRoute(r); current != nil {
+				if template, err := current.GetPathTemplate(); err == nil {
+					route = template
+				}
+			}
+			method, status := metricsMethod(r.Method), strconv.Itoa(recorder.status)
+			requests.Inc(method, route, status)
+			durations.Observe(time.Since(start).Seconds(), method, route, status)
+		})
+	}
+}
+
+// metricsMethod returns the method label of a request, other for methods HTTP does not define,
+// so that clients cannot create series at will.
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	}
+	return "other"
+}
+
+// statusRecorder is a http.ResponseWriter that remembers the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+// WriteHeader records the status code and sends it.
+func (s *statusRecorder) WriteHeader(status int) {
+	if !s.wrote {
+		s.status = status
+		s.wrote = true
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Write sends the body, which implies status 200 if no status was sent.
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wrote = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying http.ResponseWriter for http.ResponseController.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
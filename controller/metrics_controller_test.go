@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+	"task_manager_go/metrics"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	requestMetrics := RequestMetrics(registry)
+	r := mux.NewRouter()
+	r.Use(requestMetrics)
+	r.NotFoundHandler = requestMetrics(http.HandlerFunc(NotFound))
+	r.HandleFunc("/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] == "3" {
+			writeProblem(w, r, http.StatusNotFound, "Task not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+	}).Methods("GET")
+	r.HandleFunc("/metrics", NewMetricsController(registry).Metrics).Methods("GET")
+
+	serve(r, "GET", "/tasks/1", "")
+	serve(r, "GET", "/tasks/2", "")
+	serve(r, "GET", "/tasks/3", "")
+	serve(r, "GET", "/unknown/path", "")
+
+	recorder := serve(r, "GET", "/metrics", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, metrics.ContentType, recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	assert.Contains(t, body, "# TYPE http_requests_total counter\n")
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/tasks/{id}",status="200"} 2`+"\n")
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/tasks/{id}",status="404"} 1`+"\n")
+	assert.Contains(t, body, `http_requests_total{method="GET",route="unmatched",status="404"} 1`+"\n")
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/tasks/{id}",status="200"} 2`+"\n")
+	assert.NotContains(t, body, "/tasks/1")
+}
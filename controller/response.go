@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager_go/model"
+	"task_manager_go/service"
+	"task_manager_go/validation"
+)
+
+// problem is the RFC 7807 problem details body of every error response.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors lists the rejected fields of a 422 response caused by invalid fields
+	Errors []validation.FieldError `json:"errors,omitempty"`
+}
+
+// decodeBody decodes the JSON request body into v and closes it.
+// Fields that v does not have are rejected.
+// Writes a 422 response for unknown fields and invalid field values and a 400 response for malformed bodies,
+// and returns false when the body cannot be decoded.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			log.Println("Failed to close request body:", err)
+		}
+	}()
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if fieldErrors := decodeFieldErrors(err); fieldErrors != nil {
+		writeError(w, r, fieldErrors)
+		return false
+	}
+	if errors.Is(err, model.ErrValidation) {
+		writeProblem(w, r, http.StatusUnprocessableEntity, err.Error())
+		return false
+	}
+	if errors.Is(err, io.EOF) {
+		writeProblem(w, r, http.StatusBadRequest, "Request body is required")
+		return false
+	}
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Failed to decode request body")
+		return false
+	}
+	return true
+}
+
+// decodeFieldErrors returns the field errors for a decoding error caused by a single field,
+// that is an unknown field or a value of the wrong type, and nil for any other error.
+func decodeFieldErrors(err error) validation.Errors {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return validation.Errors{{Field: typeError.Field, Message: "must be a " + typeError.Type.String()}}
+	}
+	// encoding/json reports unknown fields only through the error message
+	if quoted, ok := strings.CutPrefix(errorMessage(err), "json: unknown field "); ok {
+		field, unquoteErr := strconv.Unquote(quoted)
+		if unquoteErr != nil {
+			field = quoted
+		}
+		return validation.Errors{{Field: field, Message: "is not a field that can be set"}}
+	}
+	return nil
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("Failed to encode response:", err)
+	}
+}
+
+// writeProblem writes a problem+json response with the given status code and detail.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	sendProblem(w, newProblem(r, status, detail))
+}
+
+// newProblem returns the problem details for a response to r with the given status code and detail.
+func newProblem(r *http.Request, status int, detail string) problem {
+	return problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+}
+
+// sendProblem writes p as a problem+json response.
+func sendProblem(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Println("Failed to encode response:", err)
+	}
+}
+
+// writeError writes the problem+json response for an error returned by a service or a validation.
+// Field errors are listed in the errors member. Unexpected errors are logged and reported without details.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("%s %s failed: %v", r.Method, r.URL.Path, err)
+		writeProblem(w, r, status, "An unexpected error occurred")
+		return
+	}
+	if status == http.StatusServiceUnavailable {
+		writeProblem(w, r, status, "The request timed out or was cancelled")
+		return
+	}
+	p := newProblem(r, status, err.Error())
+	var fieldErrors validation.Errors
+	if errors.As(err, &fieldErrors) {
+		p.Detail = "The request has invalid fields"
+		p.Errors = fieldErrors
+	}
+	sendProblem(w, p)
+}
+
+// errorStatus maps the kind of an error returned by a service to an HTTP status code.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, errors.ErrUnsupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorMessage returns the message of err, or an empty string for nil.
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// NotFound handles requests to unknown routes with a problem+json response.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusNotFound, "No such endpoint")
+}
+
+// MethodNotAllowed handles requests with a method a route does not support with a problem+json response.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusMethodNotAllowed, r.Method+" is not supported here")
+}
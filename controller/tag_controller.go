@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"net/http"
+	"task_manager_go/service"
+)
+
+// TagController handles HTTP requests for tags and their attachment to tasks.
+type TagController struct {
+	service *service.TagService
+}
+
+// NewTagController creates a new instance of TagController with the specified service.
+func NewTagController(service *service.TagService) *TagController {
+	return &TagController{service: service}
+}
+
+// tagRequest is the JSON body for creating and renaming a tag.
+type tagRequest struct {
+	Name string
+}
+
+// mergeRequest is the JSON body of POST /tags/{id}/merge.
+type mergeRequest struct {
+	// TargetId is the id of the tag that replaces the merged tag
+	TargetId uint
+}
+
+// attachRequest is the JSON body of POST /tasks/{id}/tags.
+type attachRequest struct {
+	// Names are the names of the tags to attach, missing tags are created
+	Names []string
+}
+
+// GetAllTags handles GET request to retrieve all tags.
+// Returns a JSON array of tags or an error response.
+func (c *TagController) GetAllTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := c.service.GetAllTags(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// CreateTag handles POST request to create a new tag.
+// Expects the tag name in JSON format in the request body.
+// Returns the created tag, 409 if the name is taken, or an error response.
+func (c *TagController) CreateTag(w http.ResponseWriter, r *http.Request) {
+	var request tagRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	tag, err := c.service.CreateTag(r.Context(), request.Name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, tag)
+}
+
+// GetTag handles GET request to retrieve a tag by its ID.
+// Expects tag ID in the URL path.
+// Returns the found tag or an error response.
+func (c *TagController) GetTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathId(w, r, "id", "tag")
+	if !ok {
+		return
+	}
+	tag, err := c.service.GetTag(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// RenameTag handles PATCH request to rename a tag.
+// Expects tag ID in the URL path and the new name in JSON format in the request body.
+// Returns the renamed tag, 409 if the name is taken, or an error response.
+func (c *TagController) RenameTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathId(w, r, "id", "tag")
+	if !ok {
+		return
+	}
+	var request tagRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	tag, err := c.service.RenameTag(r.Context(), id, request.Name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// MergeTag handles POST request to merge a tag into another tag.
+// Expects the source tag ID in the URL path and the target tag ID in JSON format in the request body.
+// Returns the target tag or an error response.
+func (c *TagController) MergeTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathId(w, r, "id", "tag")
+	if !ok {
+		return
+	}
+	var request mergeRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	tag, err := c.service.MergeTags(r.Context(), id, request.TargetId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// DeleteTag handles DELETE request to remove a tag from the system and from every task.
+// Expects tag ID in the URL path.
+// Returns success or error response.
+func (c *TagController) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathId(w, r, "id", "tag")
+	if !ok {
+		return
+	}
+	if err := c.service.DeleteTag(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTaskTags handles GET request to retrieve the tags of a task.
+// Expects task ID in the URL path.
+// Returns a JSON array of tags or an error response.
+func (c *TagController) GetTaskTags(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	tags, err := c.service.GetTaskTags(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// AttachTags handles POST request to attach tags to a task by name.
+// Expects task ID in the URL path and the tag names in JSON format in the request body.
+// Returns all tags of the task or an error response.
+func (c *TagController) AttachTags(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	var request attachRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	tags, err := c.service.AttachTags(r.Context(), id, request.Names)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// DetachTag handles DELETE request to detach a tag from a task.
+// Expects task ID and tag ID in the URL path.
+// Returns success or error response.
+func (c *TagController) DetachTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	tagId, ok := pathId(w, r, "tagId", "tag")
+	if !ok {
+		return
+	}
+	if err := c.service.DetachTag(r.Context(), id, tagId); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,12 +1,14 @@
 package controller
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"task_manager_go/model"
 	"task_manager_go/service"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -22,134 +24,224 @@ func NewTaskController(service *service.TaskService) *TaskController {
 	return &TaskController{service: service}
 }
 
-// GetAllTasks handles GET request to retrieve all tasks.
-// Returns a JSON array of tasks or an error response.
+// GetAllTasks handles GET request to retrieve tasks.
+// Accepts filtering, sorting and pagination query parameters.
+// Returns a JSON page of tasks with the total count, 400 for invalid query parameters, or an error response.
+// Date-sorted pages carry a next_cursor token and a Link header pointing to the next page.
 func (c *TaskController) GetAllTasks(w http.ResponseWriter, r *http.Request) {
-	tasks, err := c.service.GetAllTasks()
+	query, err := parseTaskQuery(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(tasks)
+	page, err := c.service.FindTasks(r.Context(), query)
 	if err != nil {
-		http.Error(w, "Failed to encode tasks", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
+
+	response := taskListResponse{
+		Items:  page.Tasks,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	if response.Items == nil {
+		response.Items = []model.Task{}
+	}
+	if page.NextCursor != nil {
+		response.NextCursor = page.NextCursor.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextPageURL(r, response.NextCursor)))
+	}
+	writeJSON(w, http.StatusOK, response)
 }
 
 // CreateTask handles POST request to create a new task.
 // Expects task data in JSON format in the request body.
-// Returns the created task or an error response.
+// Returns the created task with its ETag, 422 with the invalid fields, 409 for a parent cycle, or an error response.
 func (c *TaskController) CreateTask(w http.ResponseWriter, r *http.Request) {
-	var createdTask model.Task
-	err := json.NewDecoder(r.Body).Decode(&createdTask)
-	if err != nil {
-		http.Error(w, "Failed to decode tasks", http.StatusBadRequest)
+	var request createTaskRequest
+	if !decodeBody(w, r, &request) {
 		return
 	}
-	defer func() {
-		if err := r.Body.Close(); err != nil {
-			log.Println("Failed to close request body:", err)
-		}
-	}()
-	log.Printf("Received task data: %+v\n", createdTask)
-	createdTaskPtr, err := c.service.CreateTask(createdTask)
+	log.Printf("Received task data: %+v\n", request)
+	if err := request.validate(time.Now()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	createdTaskPtr, err := c.service.As(requestActor(r)).CreateTask(r.Context(), request.task())
 	if err != nil {
-		http.Error(w, "Failed to create task", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdTaskPtr)
+	w.Header().Set("ETag", taskETag(createdTaskPtr))
+	writeJSON(w, http.StatusCreated, createdTaskPtr)
 }
 
 // FindTaskById handles GET request to retrieve a task by its ID.
 // Expects task ID in the URL path.
-// Returns the found task or an error response.
+// Returns the found task with its checklist and completion percentage and the ETag of the task,
+// 304 if the If-None-Match header lists that ETag, 404 if it does not exist, or an error response.
 func (c *TaskController) FindTaskById(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	log.Printf("Received ID: %v\n", vars["id"])
-
-	id, err := strconv.Atoi(vars["id"])
-	if err != nil {
-		log.Println("failed to convert variable")
+	id, ok := pathTaskId(w, r)
+	if !ok {
 		return
 	}
-	log.Printf("Parsed ID: %d\n", id)
 
-	task, err := c.service.GetTaskByID(uint(id))
+	task, err := c.service.GetTaskDetails(r.Context(), id)
 	if err != nil {
-		log.Println("no task with id", id)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
+		return
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(task)
-	if err != nil {
-		http.Error(w, "Failed to encode task", http.StatusInternalServerError)
+	if notModified(w, r, task.Task) {
 		return
 	}
+	w.Header().Set("ETag", taskETag(task.Task))
+	writeJSON(w, http.StatusOK, task)
 }
 
-// UpdateTaskById handles PATCH request to update an existing task.
-// Expects task ID in the URL path and updated task data in JSON format in the request body.
-// Returns the updated task or an error response.
+// UpdateTaskById handles PATCH request to update some fields of an existing task.
+// Expects task ID in the URL path and a JSON Merge Patch or a JSON Patch of the task in the request body;
+// only the fields the patch names are changed. An If-Match header makes the change conditional on the ETag of the task.
+// Without it, the patch is stored only if the task did not change while it was applied, and applied again
+// to the changed task otherwise, so that concurrent changes of other fields are never overwritten.
+// Returns the updated task, 404 if it does not exist, 412 if the task does not match If-Match,
+// 415 for other patch formats, 422 with the invalid fields,
+// 409 for a failing test operation, a disallowed status transition, open blockers, a parent cycle
+// or a task that kept changing, or another error response.
 func (c *TaskController) UpdateTaskById(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	log.Printf("Received ID: %v\n", vars["id"])
-
-	id, err := strconv.Atoi(vars["id"])
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	current, err := c.service.GetTaskByID(r.Context(), id)
 	if err != nil {
-		log.Println("failed to convert variable")
+		writeError(w, r, err)
+		return
+	}
+	version, ok := preconditionVersion(w, r, current)
+	if !ok {
+		return
+	}
+	apply, ok := decodePatch(w, r)
+	if !ok {
 		return
 	}
-	var updatedTask model.Task
 
-	log.Printf("Parsed ID: %d\n", id)
+	for attempt := 1; ; attempt++ {
+		document, err := apply(fieldsOf(current).document())
+		var fields taskFields
+		if err == nil {
+			fields, err = fieldsFromDocument(document)
+		}
+		if err == nil {
+			err = updateTaskRequest{fields}.validate()
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		task := fields.task()
+		task.Version = current.Version
+		updated, err := c.service.As(requestActor(r)).UpdateTask(r.Context(), id, task)
+		if errors.Is(err, service.ErrPreconditionFailed) && version == 0 {
+			if attempt == maxPatchAttempts {
+				writeProblem(w, r, http.StatusConflict, "The task kept changing while it was patched, try again")
+				return
+			}
+			if current, err = c.service.GetTaskByID(r.Context(), id); err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		log.Println("update complete")
+		w.Header().Set("ETag", taskETag(updated))
+		writeJSON(w, http.StatusOK, updated)
+		return
+	}
+}
 
-	err = json.NewDecoder(r.Body).Decode(&updatedTask)
-	if err != nil {
-		http.Error(w, "Failed to decode tasks", http.StatusBadRequest)
+// ReplaceTask handles PUT request to replace every client-settable field of an existing task.
+// Expects task ID in the URL path and the complete task in JSON format in the request body.
+// An If-Match header makes the change conditional on the ETag of the task.
+// Returns the updated task, 404 if it does not exist, 412 if the task does not match If-Match,
+// 422 with the invalid fields, 409 for a disallowed status transition, open blockers or a parent cycle,
+// or another error response.
+func (c *TaskController) ReplaceTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	var request updateTaskRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	if err := request.validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	version, ok := c.ifMatchVersion(w, r, id)
+	if !ok {
 		return
 	}
-	defer func() {
-		if err := r.Body.Close(); err != nil {
-			log.Println("Failed to close request body:", err)
-		}
-	}()
 
-	w.Header().Set("Content-Type", "application/json")
+	c.updateTask(w, r, id, version, request.task())
+}
 
-	updatedTaskPtr, err := c.service.UpdateTask(uint(id), updatedTask)
-	log.Println("task: ", updatedTask)
+// updateTask stores the new fields of a task, based on the given version or on any version if it is 0,
+// and writes the updated task with its ETag or an error response.
+func (c *TaskController) updateTask(w http.ResponseWriter, r *http.Request, id uint, version uint, task model.Task) {
+	task.Version = version
+	updatedTaskPtr, err := c.service.As(requestActor(r)).UpdateTask(r.Context(), id, task)
 	if err != nil {
-		http.Error(w, "failed with update task", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 	log.Println("update complete")
-	json.NewEncoder(w).Encode(updatedTaskPtr)
+	w.Header().Set("ETag", taskETag(updatedTaskPtr))
+	writeJSON(w, http.StatusOK, updatedTaskPtr)
 }
 
 // DeleteById handles DELETE request to remove a task.
-// Expects task ID in the URL path.
-// Returns success or error response.
+// Expects task ID in the URL path. An If-Match header makes the deletion conditional on the ETag of the task.
+// Returns 204 on success, 404 if the task does not exist, 412 if the task does not match If-Match,
+// 409 if its subtasks protect it, or an error response.
 func (c *TaskController) DeleteById(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	log.Printf("Received ID: %v\n", vars["id"])
-
-	id, err := strconv.Atoi(vars["id"])
-	if err != nil {
-		log.Println("failed to convert variable")
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	version, ok := c.ifMatchVersion(w, r, id)
+	if !ok {
 		return
 	}
-	log.Printf("Parsed ID: %d\n", id)
 
-	err = c.service.DeleteById(uint(id))
-	if err != nil {
-		log.Println("no task with id", id)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := c.service.As(requestActor(r)).DeleteVersion(r.Context(), id, version); err != nil {
+		writeError(w, r, err)
+		return
 	}
 	log.Println("deleting complete")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathId reads the numeric path variable with the given name.
+// Writes a 400 response naming the entity and returns false when the variable is not a valid id.
+func pathId(w http.ResponseWriter, r *http.Request, name, entity string) (uint, bool) {
+	value := mux.Vars(r)[name]
+	id, err := strconv.ParseUint(value, 10, 0)
+	if err != nil || id == 0 {
+		writeProblem(w, r, http.StatusBadRequest, fmt.Sprintf("invalid %s id %q", entity, value))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// pathTaskId reads the task id from the "id" path variable, see pathId.
+func pathTaskId(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	return pathId(w, r, "id", "task")
 }
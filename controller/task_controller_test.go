@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"task_manager_go/service"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouter() *mux.Router {
+	taskController := NewTaskController(service.NewTaskService(repository.NewMockTaskRepository()))
+	r := mux.NewRouter()
+	r.NotFoundHandler = http.HandlerFunc(NotFound)
+	r.HandleFunc("/tasks", taskController.CreateTask).Methods("POST")
+	r.HandleFunc("/tasks/trash", taskController.GetTrash).Methods("GET")
+	r.HandleFunc("/tasks/{id}/restore", taskController.RestoreTask).Methods("POST")
+	r.HandleFunc("/tasks/{id}", taskController.FindTaskById).Methods("GET")
+	r.HandleFunc("/tasks/{id}", taskController.UpdateTaskById).Methods("PATCH")
+	r.HandleFunc("/tasks/{id}", taskController.ReplaceTask).Methods("PUT")
+	r.HandleFunc("/tasks/{id}", taskController.DeleteById).Methods("DELETE")
+	return r
+}
+
+func serve(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder
+}
+
+func serveWithHeader(r http.Handler, method, path, header, value, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set(header, value)
+	r.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func servePatch(r http.Handler, path, contentType, body string) *httptest.ResponseRecorder {
+	return serveWithHeader(r, "PATCH", path, "Content-Type", contentType, body)
+}
+
+func TestTaskController_ErrorResponses(t *testing.T) {
+	r := newTestRouter()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"non-numeric id", "GET", "/tasks/abc", "", http.StatusBadRequest},
+		{"missing task", "GET", "/tasks/42", "", http.StatusNotFound},
+		{"delete missing task", "DELETE", "/tasks/42", "", http.StatusNotFound},
+		{"malformed body", "POST", "/tasks", "{", http.StatusBadRequest},
+		{"unknown status", "POST", "/tasks", `{"Name":"Task","Status":"Pending"}`, http.StatusUnprocessableEntity},
+		{"unknown route", "GET", "/nothing", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(r, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, "application/problem+json", recorder.Header().Get("Content-Type"))
+
+			var body problem
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+			assert.Equal(t, tt.status, body.Status)
+			assert.Equal(t, http.StatusText(tt.status), body.Title)
+			assert.Equal(t, tt.path, body.Instance)
+		})
+	}
+}
+
+func TestTaskController_Conflict(t *testing.T) {
+	r := newTestRouter()
+
+	assert.Equal(t, http.StatusCreated, serve(r, "POST", "/tasks", `{"Name":"Task","Status":"Done"}`).Code)
+	recorder := serve(r, "PATCH", "/tasks/1", `{"Name":"Task","Status":"Cancelled"}`)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, http.StatusNoContent, serve(r, "DELETE", "/tasks/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(r, "GET", "/tasks/1", "").Code)
+}
+
+func TestTaskController_FieldErrors(t *testing.T) {
+	r := newTestRouter()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		fields []string
+	}{
+		{"empty task", "POST", "/tasks", `{}`, []string{"Name"}},
+		{"server-managed id", "POST", "/tasks", `{"Name":"Task","Id":7}`, []string{"Id"}},
+		{"server-managed date", "POST", "/tasks", `{"Name":"Task","Date":"2030-01-01T00:00:00Z"}`, []string{"Date"}},
+		{"wrong type", "POST", "/tasks", `{"Name":5}`, []string{"Name"}},
+		{"several fields", "POST", "/tasks", `{"Name":" ","Status":"Pending","Priority":"Urgent","DueDate":"2000-01-01T00:00:00Z"}`,
+			[]string{"Name", "Status", "Priority", "DueDate"}},
+		{"bad recurrence", "POST", "/tasks", `{"Name":"Task","Recurrence":"FREQ=HOURLY","TimeZone":"Mars/Olympus"}`, []string{"TimeZone"}},
+		{"replace without status", "PUT", "/tasks/1", `{"Name":"Task"}`, []string{"Status", "Priority"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(r, tt.method, tt.path, tt.body)
+			assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+			var body problem
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+			fields := make([]string, len(body.Errors))
+			for i, fieldError := range body.Errors {
+				fields[i] = fieldError.Field
+				assert.NotEmpty(t, fieldError.Message)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestTaskController_Patch(t *testing.T) {
+	r := newTestRouter()
+
+	assert.Equal(t, http.StatusCreated, serve(r, "POST", "/tasks",
+		`{"Name":"Task","Assignee":"alex","DueDate":"2030-01-01T00:00:00Z"}`).Code)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		expected    map[string]any
+	}{
+		{"plain json merges", "application/json", `{"Status":"InProgress"}`, http.StatusOK,
+			map[string]any{"Name": "Task", "Assignee": "alex", "Status": "InProgress"}},
+		{"merge patch", "application/merge-patch+json; charset=utf-8", `{"Priority":"High","Description":"text"}`, http.StatusOK,
+			map[string]any{"Name": "Task", "Status": "InProgress", "Priority": "High", "Description": "text"}},
+		{"merge patch null clears", mergePatchType, `{"Assignee":null,"DueDate":null}`, http.StatusOK,
+			map[string]any{"Name": "Task", "Assignee": "", "DueDate": nil, "Description": "text"}},
+		{"json patch", jsonPatchType,
+			`[{"op":"test","path":"/Status","value":"InProgress"},{"op":"replace","path":"/Name","value":"Renamed"},{"op":"copy","from":"/Name","path":"/Description"}]`,
+			http.StatusOK, map[string]any{"Name": "Renamed", "Description": "Renamed", "Status": "InProgress"}},
+		{"json patch remove", jsonPatchType, `[{"op":"remove","path":"/Description"}]`, http.StatusOK,
+			map[string]any{"Name": "Renamed", "Description": ""}},
+		{"failing test", jsonPatchType, `[{"op":"test","path":"/Status","value":"New"},{"op":"replace","path":"/Name","value":"x"}]`,
+			http.StatusConflict, nil},
+		{"missing path", jsonPatchType, `[{"op":"replace","path":"/Nothing","value":"x"}]`, http.StatusUnprocessableEntity, nil},
+		{"read-only field", jsonPatchType, `[{"op":"add","path":"/Id","value":7}]`, http.StatusUnprocessableEntity, nil},
+		{"merge read-only field", mergePatchType, `{"Date":"2030-01-01T00:00:00Z"}`, http.StatusUnprocessableEntity, nil},
+		{"blank name", mergePatchType, `{"Name":""}`, http.StatusUnprocessableEntity, nil},
+		{"remove status", mergePatchType, `{"Status":null}`, http.StatusUnprocessableEntity, nil},
+		{"not an object", jsonPatchType, `[{"op":"replace","path":"","value":[]}]`, http.StatusUnprocessableEntity, nil},
+		{"unsupported format", "text/plain", `Name=x`, http.StatusUnsupportedMediaType, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := servePatch(r, "/tasks/1", tt.contentType, tt.body)
+			assert.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.expected == nil {
+				return
+			}
+			var task map[string]any
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&task))
+			for field, value := range tt.expected {
+				assert.Equal(t, value, task[field], field)
+			}
+		})
+	}
+
+	recorder := servePatch(r, "/tasks/1", "text/plain", "")
+	assert.Equal(t, "application/merge-patch+json, application/json-patch+json", recorder.Header().Get("Accept-Patch"))
+	assert.Equal(t, http.StatusNotFound, servePatch(r, "/tasks/42", mergePatchType, `{"Name":"x"}`).Code)
+}
+
+func TestTaskController_ConditionalRequests(t *testing.T) {
+	r := newTestRouter()
+
+	recorder := serve(r, "POST", "/tasks", `{"Name":"Task"}`)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+
+	recorder = serve(r, "GET", "/tasks/1", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+
+	recorder = serveWithHeader(r, "GET", "/tasks/1", "If-None-Match", `W/"1"`, "")
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+
+	recorder = serveWithHeader(r, "PATCH", "/tasks/1", "If-Match", `"1"`, `{"Status":"InProgress"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+
+	recorder = serveWithHeader(r, "GET", "/tasks/1", "If-None-Match", `"1"`, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	tests := []struct {
+		name    string
+		method  string
+		ifMatch string
+		body    string
+		status  int
+	}{
+		{"stale patch", "PATCH", `"1"`, `{"Name":"Lost"}`, http.StatusPreconditionFailed},
+		{"weak tag", "PUT", `W/"2"`, `{"Name":"Lost","Status":"New","Priority":"Low"}`, http.StatusPreconditionFailed},
+		{"stale delete", "DELETE", `"1"`, "", http.StatusPreconditionFailed},
+		{"stale tag in list", "PUT", `"1", "2"`, `{"Name":"Replaced","Status":"InProgress","Priority":"Low"}`, http.StatusOK},
+		{"any version", "PATCH", `*`, `{"Name":"Any"}`, http.StatusOK},
+		{"current delete", "DELETE", `"4"`, "", http.StatusNoContent},
+		{"deleted task", "DELETE", `*`, "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serveWithHeader(r, tt.method, "/tasks/1", "If-Match", tt.ifMatch, tt.body)
+			assert.Equal(t, tt.status, recorder.Code, recorder.Body.String())
+			if tt.status == http.StatusPreconditionFailed {
+				assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestTaskController_Trash(t *testing.T) {
+	r := newTestRouter()
+
+	assert.Equal(t, http.StatusCreated, serve(r, "POST", "/tasks", `{"Name":"Task"}`).Code)
+	recorder := serve(r, "GET", "/tasks/trash", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `[]`, recorder.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, serve(r, "POST", "/tasks/1/restore", "").Code)
+	assert.Equal(t, http.StatusNoContent, serve(r, "DELETE", "/tasks/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(r, "GET", "/tasks/1", "").Code)
+
+	recorder = serve(r, "GET", "/tasks/trash", "")
+	var trash []map[string]any
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&trash))
+	assert.Len(t, trash, 1)
+	assert.Equal(t, "Task", trash[0]["Name"])
+	assert.NotNil(t, trash[0]["DeletedAt"])
+
+	recorder = serve(r, "POST", "/tasks/1/restore", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, serve(r, "GET", "/tasks/1", "").Code)
+}
+
+func TestQueryTimeout(t *testing.T) {
+	handler := QueryTimeout(time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		writeError(w, r, r.Context().Err())
+	}))
+
+	recorder := serve(handler, "GET", "/tasks", "")
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "timed out")
+}
+
+// racingTaskRepository changes a task concurrently before each of its first updates.
+type racingTaskRepository struct {
+	*repository.MockTaskRepository
+	races int
+}
+
+func (r *racingTaskRepository) UpdateTaskById(ctx context.Context, id uint, task model.Task) (model.Task, error) {
+	if r.races > 0 {
+		r.races--
+		current, err := r.MockTaskRepository.FindById(ctx, id)
+		if err != nil {
+			return model.Task{}, err
+		}
+		current.Assignee += "+"
+		if _, err := r.MockTaskRepository.UpdateTaskById(ctx, id, current); err != nil {
+			return model.Task{}, err
+		}
+	}
+	return r.MockTaskRepository.UpdateTaskById(ctx, id, task)
+}
+
+func TestTaskController_PatchConcurrentChange(t *testing.T) {
+	repo := &racingTaskRepository{MockTaskRepository: repository.NewMockTaskRepository()}
+	taskController := NewTaskController(service.NewTaskService(repo))
+	r := mux.NewRouter()
+	r.HandleFunc("/tasks", taskController.CreateTask).Methods("POST")
+	r.HandleFunc("/tasks/{id}", taskController.UpdateTaskById).Methods("PATCH")
+	assert.Equal(t, http.StatusCreated, serve(r, "POST", "/tasks", `{"Name":"Task","Assignee":"alex"}`).Code)
+
+	repo.races = 2
+	recorder := serve(r, "PATCH", "/tasks/1", `{"Name":"Renamed"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var task map[string]any
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&task))
+	assert.Equal(t, "Renamed", task["Name"])
+	assert.Equal(t, "alex++", task["Assignee"])
+	assert.Equal(t, `"4"`, recorder.Header().Get("ETag"))
+
+	repo.races = 1
+	recorder = serveWithHeader(r, "PATCH", "/tasks/1", "If-Match", `"4"`, `{"Name":"Lost"}`)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	repo.races = maxPatchAttempts
+	recorder = serve(r, "PATCH", "/tasks/1", `{"Name":"Lost"}`)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
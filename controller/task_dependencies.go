@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+)
+
+// dependencyRequest is the JSON body of POST /tasks/{id}/dependencies.
+type dependencyRequest struct {
+	// BlockerId is the id of the task that has to be done first
+	BlockerId uint
+}
+
+// AddDependency handles POST request to make a task depend on another task.
+// Expects task ID in the URL path and the blocker ID in JSON format in the request body.
+// Returns the created dependency, 404 if either task does not exist, 409 if it would create a cycle,
+// or an error response.
+func (c *TaskController) AddDependency(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	var request dependencyRequest
+	if !decodeBody(w, r, &request) {
+		return
+	}
+	if request.BlockerId == 0 {
+		writeProblem(w, r, http.StatusUnprocessableEntity, "BlockerId is required")
+		return
+	}
+
+	dependency, err := c.service.AddDependency(r.Context(), id, request.BlockerId)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dependency)
+}
+
+// RemoveDependency handles DELETE request to remove the dependency of a task on a blocker.
+// Expects task ID and blocker ID in the URL path.
+// Returns 204 on success, 404 if the dependency does not exist, or an error response.
+func (c *TaskController) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	blockerId, ok := pathId(w, r, "blockerId", "blocker")
+	if !ok {
+		return
+	}
+
+	if err := c.service.RemoveDependency(r.Context(), id, blockerId); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDependencies handles GET request to retrieve the transitive dependency graph of a task.
+// Expects task ID in the URL path.
+// Returns the graph with a topological order of its tasks, 404 if the task does not exist, or an error response.
+func (c *TaskController) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+
+	graph, err := c.service.GetDependencyGraph(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, graph)
+}
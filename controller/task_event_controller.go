@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"net/http"
+	"task_manager_go/model"
+	"task_manager_go/service"
+)
+
+// TaskEventController handles HTTP requests for the event log of tasks.
+type TaskEventController struct {
+	service *service.TaskEventService
+}
+
+// NewTaskEventController creates a new instance of TaskEventController with the specified service.
+func NewTaskEventController(service *service.TaskEventService) *TaskEventController {
+	return &TaskEventController{service: service}
+}
+
+// rebuildResponse is the JSON body returned after rebuilding the projection.
+type rebuildResponse struct {
+	Rebuilt int `json:"rebuilt"`
+}
+
+// GetEvents handles GET request to retrieve the events of a task, oldest first.
+// Expects task ID in the URL path.
+// Returns a JSON array of events, 501 if tasks are not stored as events, or an error response.
+func (c *TaskEventController) GetEvents(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := c.service.Events(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if events == nil {
+		events = []model.TaskEvent{}
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// GetTaskAt handles GET request to retrieve a task as it was at a moment in the past.
+// Expects task ID in the URL path and the moment in the at query parameter (RFC 3339).
+// Returns the task, 400 for a missing or invalid moment, 404 if the task did not exist at that moment,
+// 501 if tasks are not stored as events, or an error response.
+func (c *TaskEventController) GetTaskAt(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	at, err := parseTimeParam(r.URL.Query().Get("at"))
+	if err != nil || at == nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid at, expected an RFC 3339 timestamp")
+		return
+	}
+
+	task, err := c.service.TaskAt(r.Context(), id, *at)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// RebuildProjection handles POST request to replace the current tasks with the state replayed from their events.
+// Returns the number of replayed tasks, 501 if tasks are not stored as events, or an error response.
+func (c *TaskEventController) RebuildProjection(w http.ResponseWriter, r *http.Request) {
+	rebuilt, err := c.service.RebuildProjection(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rebuildResponse{Rebuilt: rebuilt})
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"task_manager_go/repository"
+	"task_manager_go/service"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskEventController(t *testing.T) {
+	repo := repository.NewMockTaskEventRepository()
+	taskController := NewTaskController(service.NewTaskService(repo))
+	eventController := NewTaskEventController(service.NewTaskEventService(repo))
+	r := mux.NewRouter()
+	r.HandleFunc("/tasks", taskController.CreateTask).Methods("POST")
+	r.HandleFunc("/tasks/{id}", taskController.UpdateTaskById).Methods("PATCH")
+	r.HandleFunc("/tasks/{id}/events", eventController.GetEvents).Methods("GET")
+	r.HandleFunc("/tasks/{id}/snapshot", eventController.GetTaskAt).Methods("GET")
+	r.HandleFunc("/projections/rebuild", eventController.RebuildProjection).Methods("POST")
+
+	assert.Equal(t, http.StatusCreated, serve(r, "POST", "/tasks", `{"Name":"Task"}`).Code)
+	created := time.Now()
+	assert.Equal(t, http.StatusOK, serve(r, "PATCH", "/tasks/1", `{"Name":"Renamed"}`).Code)
+
+	recorder := serve(r, "GET", "/tasks/1/events", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var events []struct {
+		Type   string
+		Fields map[string]any
+	}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&events))
+	assert.Len(t, events, 2)
+	assert.Equal(t, "TaskRenamed", events[1].Type)
+	assert.Equal(t, "Renamed", events[1].Fields["Name"])
+
+	recorder = serve(r, "GET", "/tasks/1/snapshot?at="+url.QueryEscape(created.Format(time.RFC3339Nano)), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var task struct{ Name string }
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&task))
+	assert.Equal(t, "Task", task.Name)
+
+	assert.Equal(t, http.StatusNotFound, serve(r, "GET", "/tasks/1/snapshot?at=2000-01-01T00:00:00Z", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(r, "GET", "/tasks/1/snapshot", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(r, "GET", "/tasks/1/snapshot?at=yesterday", "").Code)
+
+	recorder = serve(r, "POST", "/projections/rebuild", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"rebuilt":1}`, recorder.Body.String())
+
+	disabled := NewTaskEventController(service.NewTaskEventService(nil))
+	r.HandleFunc("/disabled/rebuild", disabled.RebuildProjection).Methods("POST")
+	assert.Equal(t, http.StatusNotImplemented, serve(r, "POST", "/disabled/rebuild", "").Code)
+}
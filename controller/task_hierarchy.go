@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+)
+
+// GetSubtasks handles GET request to retrieve the direct subtasks of a task.
+// Expects task ID in the URL path.
+// Returns a JSON array of tasks, 404 if the task does not exist, or an error response.
+func (c *TaskController) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.service.GetSubtasks(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// GetSubtree handles GET request to retrieve a task with all of its descendants.
+// Expects task ID in the URL path.
+// Returns a JSON tree of tasks with their progress, 404 if the task does not exist, or an error response.
+func (c *TaskController) GetSubtree(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+
+	tree, err := c.service.GetSubtree(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tree)
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"task_manager_go/model"
+	"task_manager_go/patch"
+)
+
+// maxPatchAttempts is how often a PATCH without If-Match is applied to a task that keeps changing
+// before it fails with 409 Conflict.
+const maxPatchAttempts = 3
+
+const (
+	// mergePatchType is the media type of RFC 7396 JSON Merge Patch bodies
+	mergePatchType = "application/merge-patch+json"
+	// jsonPatchType is the media type of RFC 6902 JSON Patch bodies
+	jsonPatchType = "application/json-patch+json"
+)
+
+// decodePatch decodes the patch in the request body and returns a function applying it to a document,
+// which may be called again when the document changed meanwhile.
+// The Content-Type selects the patch format: JSON Merge Patch for application/merge-patch+json and application/json,
+// JSON Patch for application/json-patch+json, whose failing test operations are reported as patch.ErrTestFailed.
+// Writes a 415 response for other media types and 400 or 422 for malformed patches,
+// and returns false when the patch cannot be decoded.
+func decodePatch(w http.ResponseWriter, r *http.Request) (func(document any) (any, error), bool) {
+	mediaType := patchMediaType(r)
+	switch mediaType {
+	case mergePatchType, "application/json":
+		var mergePatch any
+		if !decodeBody(w, r, &mergePatch) {
+			return nil, false
+		}
+		return func(document any) (any, error) {
+			return patch.Merge(document, mergePatch), nil
+		}, true
+	case jsonPatchType:
+		var operations []patch.Operation
+		if !decodeBody(w, r, &operations) {
+			return nil, false
+		}
+		return func(document any) (any, error) {
+			return patch.Apply(document, operations)
+		}, true
+	default:
+		w.Header().Set("Accept-Patch", mergePatchType+", "+jsonPatchType)
+		writeProblem(w, r, http.StatusUnsupportedMediaType, "PATCH supports "+mergePatchType+" and "+jsonPatchType)
+		return nil, false
+	}
+}
+
+// patchMediaType returns the media type of the request body, application/json if the request does not name one.
+func patchMediaType(r *http.Request) string {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return "application/json"
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// document returns the fields as a JSON document that patches can be applied to.
+func (f taskFields) document() any {
+	data, _ := json.Marshal(f)
+	var document any
+	_ = json.Unmarshal(data, &document)
+	return document
+}
+
+// fieldsFromDocument decodes the fields from a patched document.
+// Members that are not client-settable fields and values of the wrong type are reported as field errors.
+func fieldsFromDocument(document any) (taskFields, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return taskFields{}, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var fields taskFields
+	if err := decoder.Decode(&fields); err != nil {
+		if fieldErrors := decodeFieldErrors(err); fieldErrors != nil {
+			return taskFields{}, fieldErrors
+		}
+		return taskFields{}, model.NewError(model.ErrValidation, "the patched task must be a JSON object")
+	}
+	return fields, nil
+}
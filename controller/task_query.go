@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"task_manager_go/model"
+	"time"
+)
+
+// taskListResponse is the JSON body returned by GET /tasks.
+type taskListResponse struct {
+	Items      []model.Task `json:"items"`
+	Total      int64        `json:"total"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// parseTaskQuery builds a TaskQuery from the query parameters of a GET /tasks request.
+// Supported parameters are status, name, priority, assignee, tag (repeatable), tag_match,
+// created_after, created_before, due_after, due_before, sort, order, limit, offset and cursor.
+func parseTaskQuery(r *http.Request) (model.TaskQuery, error) {
+	values := r.URL.Query()
+	query := model.TaskQuery{
+		NameContains: values.Get("name"),
+		Assignee:     values.Get("assignee"),
+		Tags:         values["tag"],
+		SortBy:       values.Get("sort"),
+	}
+
+	switch match := values.Get("tag_match"); match {
+	case "", "any":
+	case "all":
+		query.MatchAllTags = true
+	default:
+		return model.TaskQuery{}, fmt.Errorf("invalid tag_match %q, expected any or all", match)
+	}
+
+	if status := values.Get("status"); status != "" {
+		parsed, err := model.ParseStatus(status)
+		if err != nil {
+			return model.TaskQuery{}, err
+		}
+		query.Status = parsed
+	}
+	if priority := values.Get("priority"); priority != "" {
+		parsed, err := model.ParsePriority(priority)
+		if err != nil {
+			return model.TaskQuery{}, err
+		}
+		query.Priority = parsed
+	}
+
+	switch order := values.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		query.SortDesc = true
+	default:
+		return model.TaskQuery{}, fmt.Errorf("invalid order %q, expected asc or desc", order)
+	}
+
+	var err error
+	if query.CreatedAfter, err = parseTimeParam(values.Get("created_after")); err != nil {
+		return model.TaskQuery{}, fmt.Errorf("invalid created_after: %w", err)
+	}
+	if query.CreatedBefore, err = parseTimeParam(values.Get("created_before")); err != nil {
+		return model.TaskQuery{}, fmt.Errorf("invalid created_before: %w", err)
+	}
+	if query.DueAfter, err = parseTimeParam(values.Get("due_after")); err != nil {
+		return model.TaskQuery{}, fmt.Errorf("invalid due_after: %w", err)
+	}
+	if query.DueBefore, err = parseTimeParam(values.Get("due_before")); err != nil {
+		return model.TaskQuery{}, fmt.Errorf("invalid due_before: %w", err)
+	}
+	if query.Limit, err = parseIntParam(values.Get("limit")); err != nil {
+		return model.TaskQuery{}, fmt.Errorf("invalid limit: %w", err)
+	}
+	if query.Offset, err = parseIntParam(values.Get("offset")); err != nil {
+		return model.TaskQuery{}, fmt.Errorf("invalid offset: %w", err)
+	}
+	if token := values.Get("cursor"); token != "" {
+		cursor, err := model.DecodeTaskCursor(token)
+		if err != nil {
+			return model.TaskQuery{}, err
+		}
+		query.After = &cursor
+	}
+	return query, nil
+}
+
+// nextPageURL returns the URL of the page following the current request, continuing from cursor.
+func nextPageURL(r *http.Request, cursor string) string {
+	values := r.URL.Query()
+	values.Set("cursor", cursor)
+	values.Del("offset")
+	next := url.URL{Path: r.URL.Path, RawQuery: values.Encode()}
+	return next.String()
+}
+
+// parseTimeParam parses an optional RFC 3339 timestamp.
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// parseIntParam parses an optional integer, returning 0 when the value is empty.
+func parseIntParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
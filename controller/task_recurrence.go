@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+)
+
+// occurrencesResponse is the JSON body returned by GET /tasks/{id}/occurrences.
+type occurrencesResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+// PreviewOccurrences handles GET request to preview the upcoming occurrences of a recurring task.
+// Expects task ID in the URL path and an optional count query parameter.
+// Returns the occurrences with the UTC offset of the recurrence time zone, 404 if the task does not exist,
+// or an error response.
+func (c *TaskController) PreviewOccurrences(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+	count, err := parseIntParam(r.URL.Query().Get("count"))
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "invalid count")
+		return
+	}
+
+	occurrences, err := c.service.PreviewOccurrences(r.Context(), id, count)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, occurrencesResponse{Occurrences: occurrences})
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/service"
+	"task_manager_go/validation"
+	"time"
+)
+
+// taskFields are the fields of a task a client can set.
+// Id, Date, the timestamps, the tags and the recurrence bookkeeping are maintained by the server,
+// so payloads naming them are rejected as having unknown fields.
+type taskFields struct {
+	Name        string
+	Description string
+	Status      string
+	Priority    string
+	DueDate     *time.Time
+	Assignee    string
+	ParentId    *uint
+	Recurrence  string
+	TimeZone    string
+}
+
+// createTaskRequest is the JSON body of POST /tasks.
+// Status and Priority may be omitted and default to New and Medium.
+type createTaskRequest struct {
+	taskFields
+}
+
+// updateTaskRequest is the JSON body of PUT /tasks/{id} and the result of a PATCH.
+// It replaces every field a client can set, so Status and Priority are required
+// and omitted optional fields are cleared.
+type updateTaskRequest struct {
+	taskFields
+}
+
+// validate checks the request and returns its field errors.
+// The due date of a new task must not lie in the past.
+func (req createTaskRequest) validate(now time.Time) error {
+	var v validation.Validator
+	req.check(&v)
+	v.NotBefore("DueDate", req.DueDate, now, "the current time")
+	return v.Err()
+}
+
+// validate checks the request and returns its field errors.
+func (req updateTaskRequest) validate() error {
+	var v validation.Validator
+	v.Required("Status", req.Status)
+	v.Required("Priority", req.Priority)
+	req.check(&v)
+	return v.Err()
+}
+
+// check records the field errors of the fields in v.
+func (f taskFields) check(v *validation.Validator) {
+	v.Required("Name", f.Name)
+	v.MaxLength("Name", f.Name, service.MaxNameLength)
+	v.MaxLength("Description", f.Description, service.MaxDescriptionLength)
+	v.OneOf("Status", f.Status, statusNames())
+	v.OneOf("Priority", f.Priority, priorityNames())
+	v.MaxLength("Assignee", f.Assignee, service.MaxAssigneeLength)
+	v.Check(f.ParentId == nil || *f.ParentId != 0, "ParentId", "must be the id of a task")
+
+	loc, err := time.LoadLocation(f.TimeZone)
+	v.Check(err == nil, "TimeZone", "must be an IANA time zone such as Europe/Berlin")
+	if err == nil && f.Recurrence != "" {
+		_, err := service.ParseRecurrenceRule(f.Recurrence, loc)
+		v.Check(err == nil, "Recurrence", errorMessage(err))
+	}
+}
+
+// task converts validated fields to a task.
+func (f taskFields) task() model.Task {
+	status, _ := model.ParseStatus(f.Status)
+	priority, _ := model.ParsePriority(f.Priority)
+	return model.Task{
+		Name:        f.Name,
+		Description: f.Description,
+		Status:      status,
+		Priority:    priority,
+		DueDate:     f.DueDate,
+		Assignee:    f.Assignee,
+		ParentId:    f.ParentId,
+		Recurrence:  f.Recurrence,
+		TimeZone:    f.TimeZone,
+	}
+}
+
+// fieldsOf returns the client-settable fields of a task.
+func fieldsOf(task model.Task) taskFields {
+	fields := taskFields{
+		Name:        task.Name,
+		Description: task.Description,
+		Status:      string(task.Status),
+		DueDate:     task.DueDate,
+		Assignee:    task.Assignee,
+		ParentId:    task.ParentId,
+		Recurrence:  task.Recurrence,
+		TimeZone:    task.TimeZone,
+	}
+	if task.Priority != 0 {
+		fields.Priority = task.Priority.String()
+	}
+	return fields
+}
+
+// statusNames returns the names of all statuses.
+func statusNames() []string {
+	names := make([]string, len(model.Statuses))
+	for i, status := range model.Statuses {
+		names[i] = string(status)
+	}
+	return names
+}
+
+// priorityNames returns the names of all priorities.
+func priorityNames() []string {
+	names := make([]string, len(model.Priorities))
+	for i, priority := range model.Priorities {
+		names[i] = priority.String()
+	}
+	return names
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+	"task_manager_go/model"
+)
+
+// GetTrash handles GET request to retrieve the deleted tasks that have not been purged yet.
+// Returns a JSON array of tasks, most recently deleted first, or an error response.
+func (c *TaskController) GetTrash(w http.ResponseWriter, r *http.Request) {
+	tasks, err := c.service.GetTrash(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if tasks == nil {
+		tasks = []model.Task{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// RestoreTask handles POST request to take a deleted task out of the trash.
+// Expects task ID in the URL path.
+// Returns the restored task with its ETag, 404 if the task is not in the trash,
+// 409 if its parent is still in the trash, or an error response.
+func (c *TaskController) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathTaskId(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := c.service.As(requestActor(r)).RestoreTask(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", taskETag(task))
+	writeJSON(w, http.StatusOK, task)
+}
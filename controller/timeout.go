@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// QueryTimeout returns a middleware that cancels the context of every request after timeout.
+// The context is passed down to the repositories, so the queries of a request that takes too long
+// or whose client went away are cancelled and the request is answered with 503 Service Unavailable.
+func QueryTimeout(timeout time.Duration) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -1,34 +1,139 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
+	"os"
 	"task_manager_go/config"
 	"task_manager_go/controller"
-	repository2 "task_manager_go/repository"
+	"task_manager_go/metrics"
 	"task_manager_go/service"
 
 	"github.com/gorilla/mux"
 )
 
 // main is the entry point of the application.
-// Initializes the database connection, sets up the dependency chain,
-// configures the router with REST API endpoints, and starts the HTTP server.
+// Loads the configuration, initializes the database connection, sets up the dependency chain,
+// configures the router with REST API endpoints, and runs the HTTP server until SIGINT or SIGTERM.
+// On shutdown it drains the server, then stops the background workers and finally closes the store.
+// With --print-config it prints the configuration instead.
 func main() {
-	db := config.InitDB()
-	repository := repository2.NewTaskRepository(db)
-	taskService := service.NewTaskService(repository)
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if cfg.PrintConfig {
+		if err := cfg.WriteRedacted(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	workers := newWorkers()
+	registry := metrics.NewRegistry()
+	repos := openRepositories(workers, registry, cfg)
+	repository := repos.Tasks
+	options := []service.Option{
+		service.WithDependencies(repos.Dependencies),
+		service.WithComments(repos.Comments),
+		service.WithChecklists(repos.Checklists),
+		service.WithAudit(repos.Audit),
+		service.WithMetrics(registry),
+	}
+	if repos.Transactor != nil {
+		options = append(options, service.WithTransactor(repos.Transactor))
+	}
+	policy, err := service.ParseChildDeletePolicy(cfg.Tasks.ChildDeletePolicy)
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	options = append(options, service.WithChildDeletePolicy(policy))
+	taskService := service.NewTaskService(repository, options...)
+	taskService.RegisterTaskGauge(registry)
+	workers.Go(func(ctx context.Context) {
+		taskService.RunTrashPurge(ctx, cfg.Tasks.TrashRetention, cfg.Tasks.TrashPurgeInterval)
+	})
 	taskController := controller.NewTaskController(taskService)
+	tagService := service.NewTagService(repos.Tags, repository, repos.Transactor)
+	tagController := controller.NewTagController(tagService)
+	commentService := service.NewCommentService(repos.Comments, repository)
+	commentController := controller.NewCommentController(commentService)
+	checklistService := service.NewChecklistService(repos.Checklists, repository)
+	checklistController := controller.NewChecklistController(checklistService)
+	auditController := controller.NewAuditController(service.NewAuditService(repos.Audit))
+	eventController := controller.NewTaskEventController(service.NewTaskEventService(repos.Events))
+	healthController := controller.NewHealthController(cfg.Server.HealthTimeout, repos.Checks...)
+	metricsController := controller.NewMetricsController(registry)
+	requestMetrics := controller.RequestMetrics(registry)
 	r := mux.NewRouter()
+	r.Use(requestMetrics, controller.QueryTimeout(cfg.Server.QueryTimeout))
+	r.NotFoundHandler = requestMetrics(http.HandlerFunc(controller.NotFound))
+	r.MethodNotAllowedHandler = requestMetrics(http.HandlerFunc(controller.MethodNotAllowed))
 	r.HandleFunc("/tasks", taskController.CreateTask).Methods("POST")
 	r.HandleFunc("/tasks", taskController.GetAllTasks).Methods("GET")
+	r.HandleFunc("/tasks/trash", taskController.GetTrash).Methods("GET")
 	r.HandleFunc("/tasks/{id}", taskController.FindTaskById).Methods("GET")
 	r.HandleFunc("/tasks/{id}", taskController.UpdateTaskById).Methods("PATCH")
+	r.HandleFunc("/tasks/{id}", taskController.ReplaceTask).Methods("PUT")
 	r.HandleFunc("/tasks/{id}", taskController.DeleteById).Methods("DELETE")
+	r.HandleFunc("/tasks/{id}/restore", taskController.RestoreTask).Methods("POST")
+	r.HandleFunc("/tasks/{id}/history", auditController.GetHistory).Methods("GET")
+	r.HandleFunc("/tasks/{id}/events", eventController.GetEvents).Methods("GET")
+	r.HandleFunc("/tasks/{id}/snapshot", eventController.GetTaskAt).Methods("GET")
+	r.HandleFunc("/tasks/{id}/subtasks", taskController.GetSubtasks).Methods("GET")
+	r.HandleFunc("/tasks/{id}/subtree", taskController.GetSubtree).Methods("GET")
+	r.HandleFunc("/tasks/{id}/occurrences", taskController.PreviewOccurrences).Methods("GET")
+	r.HandleFunc("/tasks/{id}/dependencies", taskController.GetDependencies).Methods("GET")
+	r.HandleFunc("/tasks/{id}/dependencies", taskController.AddDependency).Methods("POST")
+	r.HandleFunc("/tasks/{id}/dependencies/{blockerId}", taskController.RemoveDependency).Methods("DELETE")
+	r.HandleFunc("/tasks/{id}/tags", tagController.GetTaskTags).Methods("GET")
+	r.HandleFunc("/tasks/{id}/tags", tagController.AttachTags).Methods("POST")
+	r.HandleFunc("/tasks/{id}/tags/{tagId}", tagController.DetachTag).Methods("DELETE")
+	r.HandleFunc("/tasks/{id}/comments", commentController.ListComments).Methods("GET")
+	r.HandleFunc("/tasks/{id}/comments", commentController.AddComment).Methods("POST")
+	r.HandleFunc("/tasks/{id}/comments/{commentId}", commentController.EditComment).Methods("PATCH")
+	r.HandleFunc("/tasks/{id}/comments/{commentId}", commentController.DeleteComment).Methods("DELETE")
+	r.HandleFunc("/tasks/{id}/checklist", checklistController.GetChecklist).Methods("GET")
+	r.HandleFunc("/tasks/{id}/checklist", checklistController.AddItem).Methods("POST")
+	r.HandleFunc("/tasks/{id}/checklist/order", checklistController.ReorderItems).Methods("PUT")
+	r.HandleFunc("/tasks/{id}/checklist/{itemId}/toggle", checklistController.ToggleItem).Methods("POST")
+	r.HandleFunc("/tasks/{id}/checklist/{itemId}", checklistController.DeleteItem).Methods("DELETE")
+	r.HandleFunc("/audit", auditController.FindEntries).Methods("GET")
+	r.HandleFunc("/projections/rebuild", eventController.RebuildProjection).Methods("POST")
+	r.HandleFunc("/tags", tagController.GetAllTags).Methods("GET")
+	r.HandleFunc("/tags", tagController.CreateTag).Methods("POST")
+	r.HandleFunc("/tags/{id}", tagController.GetTag).Methods("GET")
+	r.HandleFunc("/tags/{id}", tagController.RenameTag).Methods("PATCH")
+	r.HandleFunc("/tags/{id}", tagController.DeleteTag).Methods("DELETE")
+	r.HandleFunc("/tags/{id}/merge", tagController.MergeTag).Methods("POST")
+	r.HandleFunc("/healthz", healthController.Live).Methods("GET")
+	r.HandleFunc("/readyz", healthController.Ready).Methods("GET")
+	r.HandleFunc("/version", controller.Version).Methods("GET")
+	r.HandleFunc("/metrics", metricsController.Metrics).Methods("GET")
 
-	err := http.ListenAndServe("localhost:8080", r)
-	if err != nil {
-		log.Println("err with conn localhost ", err)
-		return
+	server := &http.Server{
+		Addr:         cfg.Server.Address,
+		Handler:      r,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	serveErr := serve(server, healthController, cfg.Server)
+	if serveErr != nil {
+		log.Println("Server failed:", serveErr)
+	}
+	workers.Stop()
+	if err := repos.Close(); err != nil {
+		log.Println("Failed to close the store:", err)
+	}
+	log.Println("Server stopped")
+	if serveErr != nil {
+		os.Exit(1)
 	}
 }
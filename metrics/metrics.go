@@ -0,0 +1,405 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text exposition format written by Registry.Write.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds of histogram buckets suited to durations in seconds,
+// from 5 milliseconds to 10 seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Kinds of metrics, as written in the TYPE line of the exposition format.
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// namePattern matches valid names of metrics and labels.
+var namePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// Registry holds metrics and writes them in the Prometheus text exposition format.
+// It is safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]*described
+}
+
+// NewRegistry creates a new, empty instance of Registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*described)}
+}
+
+// metric is a family of series sharing a name, help text and label names.
+type metric interface {
+	// describe returns the kind and label names of the metric
+	describe() (kind string, labels []string)
+	// write writes the series of the metric, without the HELP and TYPE lines
+	write(ctx context.Context, w *bufio.Writer) error
+}
+
+// Sample is a value of a metric read on every scrape, with the values of its labels in the order of their names.
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// Counter is a value per combination of label values that only goes up, such as the number of requests.
+type Counter struct {
+	name   string
+	labels []string
+	series *series[float64]
+}
+
+// Histogram counts observations per combination of label values in buckets of their value, such as durations.
+type Histogram struct {
+	name    string
+	labels  []string
+	buckets []float64
+	series  *series[*histogramValue]
+}
+
+// histogramValue is the state of a single histogram series.
+type histogramValue struct {
+	// counts holds the number of observations per bucket, not cumulated, the last one above every bound
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// funcMetric is a counter or gauge whose samples are read by a function on every scrape.
+type funcMetric struct {
+	name    string
+	kind    string
+	labels  []string
+	collect func(ctx context.Context) ([]Sample, error)
+}
+
+// Counter returns the counter with the given name and label names, registering it on first use.
+// Panics if the name is invalid or already used by another kind of metric or with other labels.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return register(r, name, help, kindCounter, labels, func() *Counter {
+		return &Counter{name: name, labels: labels, series: newSeries[float64]()}
+	})
+}
+
+// Histogram returns the histogram with the given name, bucket upper bounds and label names,
+// registering it on first use. The buckets must be sorted, +Inf is added implicitly.
+// Panics if the name is invalid or already used by another kind of metric or with other labels.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if !slices.IsSorted(buckets) {
+		panic(fmt.Sprintf("metrics: buckets of %s are not sorted", name))
+	}
+	return register(r, name, help, kindHistogram, labels, func() *Histogram {
+		return &Histogram{name: name, labels: labels, buckets: buckets, series: newSeries[*histogramValue]()}
+	})
+}
+
+// GaugeFunc registers a gauge whose samples are read by collect on every scrape,
+// for values that are cheaper to read when needed than to track, such as the size of a pool.
+// Panics if the name is invalid or already registered.
+func (r *Registry) GaugeFunc(name, help string, labels []string, collect func(ctx context.Context) ([]Sample, error)) {
+	r.registerFunc(name, help, kindGauge, labels, collect)
+}
+
+// CounterFunc registers a counter whose samples are read by collect on every scrape,
+// for totals that another component already keeps.
+// Panics if the name is invalid or already registered.
+func (r *Registry) CounterFunc(name, help string, labels []string, collect func(ctx context.Context) ([]Sample, error)) {
+	r.registerFunc(name, help, kindCounter, labels, collect)
+}
+
+// registerFunc registers a metric whose samples are read by collect.
+func (r *Registry) registerFunc(name, help, kind string, labels []string, collect func(ctx context.Context) ([]Sample, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[name]; ok {
+		panic(fmt.Sprintf("metrics: %s is already registered", name))
+	}
+	checkNames(name, labels)
+	r.metrics[name] = &described{help: help, metric: &funcMetric{name: name, kind: kind, labels: labels, collect: collect}}
+}
+
+// described is a registered metric with its help text.
+type described struct {
+	help string
+	metric
+}
+
+// register returns the metric registered under name, creating it with create on first use.
+func register[M metric](r *Registry, name, help, kind string, labels []string, create func() M) M {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.metrics[name]; ok {
+		m, ok := existing.metric.(M)
+		existingKind, existingLabels := existing.describe()
+		if !ok || existingKind != kind || !slices.Equal(existingLabels, labels) {
+			panic(fmt.Sprintf("metrics: %s is already registered as a different metric", name))
+		}
+		return m
+	}
+	checkNames(name, labels)
+	m := create()
+	r.metrics[name] = &described{help: help, metric: m}
+	return m
+}
+
+// checkNames panics if the name of a metric or one of its labels is invalid.
+func checkNames(name string, labels []string) {
+	if !namePattern.MatchString(name) {
+		panic(fmt.Sprintf("metrics: invalid metric name %q", name))
+	}
+	for _, label := range labels {
+		if !namePattern.MatchString(label) || strings.HasPrefix(label, "__") || label == "le" {
+			panic(fmt.Sprintf("metrics: invalid label name %q of %s", label, name))
+		}
+	}
+}
+
+// Write writes every metric to w in the Prometheus text exposition format, sorted by name.
+// Metrics read by a function that fails are left out; their errors are returned together
+// after everything else has been written.
+func (r *Registry) Write(ctx context.Context, w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	metrics := make([]*described, 0, len(names))
+	slices.Sort(names)
+	for _, name := range names {
+		metrics = append(metrics, r.metrics[name])
+	}
+	r.mu.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	var errs []error
+	for i, m := range metrics {
+		var body strings.Builder
+		bodyWriter := bufio.NewWriter(&body)
+		if err := m.write(ctx, bodyWriter); err != nil {
+			errs = append(errs, fmt.Errorf("collecting %s: %w", names[i], err))
+			continue
+		}
+		bodyWriter.Flush()
+		kind, _ := m.describe()
+		fmt.Fprintf(buffered, "# HELP %s %s\n# TYPE %s %s\n%s", names[i], escapeHelp(m.help), names[i], kind, body.String())
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// Inc adds 1 to the counter of the given label values.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the counter of the given label values.
+// Panics if the number of label values does not match the label names of the counter.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	checkLabelValues(c.name, c.labels, labelValues)
+	c.series.update(labelValues, func(value *float64) {
+		*value += v
+	})
+}
+
+// describe returns the kind and label names of the counter.
+func (c *Counter) describe() (string, []string) {
+	return kindCounter, c.labels
+}
+
+// write writes a line per combination of label values.
+func (c *Counter) write(ctx context.Context, w *bufio.Writer) error {
+	c.series.each(func(labelValues []string, value float64) {
+		writeSample(w, c.name, c.labels, labelValues, "", "", value)
+	})
+	return nil
+}
+
+// Observe records v in the histogram of the given label values.
+// Panics if the number of label values does not match the label names of the histogram.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	checkLabelValues(h.name, h.labels, labelValues)
+	bucket, _ := slices.BinarySearch(h.buckets, v)
+	h.series.update(labelValues, func(value **histogramValue) {
+		if *value == nil {
+			*value = &histogramValue{counts: make([]uint64, len(h.buckets)+1)}
+		}
+		(*value).counts[bucket]++
+		(*value).count++
+		(*value).sum += v
+	})
+}
+
+// describe returns the kind and label names of the histogram.
+func (h *Histogram) describe() (string, []string) {
+	return kindHistogram, h.labels
+}
+
+// write writes the cumulative buckets, the sum and the count per combination of label values.
+func (h *Histogram) write(ctx context.Context, w *bufio.Writer) error {
+	h.series.each(func(labelValues []string, value *histogramValue) {
+		var cumulative uint64
+		for i, count := range value.counts {
+			cumulative += count
+			bound := math.Inf(1)
+			if i < len(h.buckets) {
+				bound = h.buckets[i]
+			}
+			writeSample(w, h.name+"_bucket", h.labels, labelValues, "le", formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, h.name+"_sum", h.labels, labelValues, "", "", value.sum)
+		writeSample(w, h.name+"_count", h.labels, labelValues, "", "", float64(value.count))
+	})
+	return nil
+}
+
+// describe returns the kind and label names of the metric.
+func (f *funcMetric) describe() (string, []string) {
+	return f.kind, f.labels
+}
+
+// write collects the samples and writes them sorted by their label values.
+func (f *funcMetric) write(ctx context.Context, w *bufio.Writer) error {
+	samples, err := f.collect(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		if len(sample.LabelValues) != len(f.labels) {
+			return fmt.Errorf("sample has %d label values, expected %d", len(sample.LabelValues), len(f.labels))
+		}
+	}
+	slices.SortFunc(samples, func(a, b Sample) int {
+		return slices.Compare(a.LabelValues, b.LabelValues)
+	})
+	for _, sample := range samples {
+		writeSample(w, f.name, f.labels, sample.LabelValues, "", "", sample.Value)
+	}
+	return nil
+}
+
+// series holds a value per combination of label values.
+type series[V any] struct {
+	mu     sync.Mutex
+	values map[string]*labeled[V]
+}
+
+// labeled is a value with the label values it belongs to.
+type labeled[V any] struct {
+	labelValues []string
+	value       V
+}
+
+// newSeries creates a new, empty instance of series.
+func newSeries[V any]() *series[V] {
+	return &series[V]{values: make(map[string]*labeled[V])}
+}
+
+// update changes the value of the given label values with fn, starting from the zero value.
+func (s *series[V]) update(labelValues []string, fn func(value *V)) {
+	// label values cannot contain the zero byte in valid UTF-8 text, so the key is unambiguous
+	key := strings.Join(labelValues, "\x00")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.values[key]
+	if !ok {
+		entry = &labeled[V]{labelValues: slices.Clone(labelValues)}
+		s.values[key] = entry
+	}
+	fn(&entry.value)
+}
+
+// each calls fn for every combination of label values sorted by the values, holding the lock.
+func (s *series[V]) each(fn func(labelValues []string, value V)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]*labeled[V], 0, len(s.values))
+	for _, entry := range s.values {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b *labeled[V]) int {
+		return slices.Compare(a.labelValues, b.labelValues)
+	})
+	for _, entry := range entries {
+		fn(entry.labelValues, entry.value)
+	}
+}
+
+// checkLabelValues panics if the number of label values does not match the label names of a metric.
+func checkLabelValues(name string, labels, labelValues []string) {
+	if len(labelValues) != len(labels) {
+		panic(fmt.Sprintf("metrics: %s has %d labels, got %d values", name, len(labels), len(labelValues)))
+	}
+}
+
+// writeSample writes a sample line, adding the label extra with extraValue unless extra is empty.
+func writeSample(w *bufio.Writer, name string, labels, labelValues []string, extra, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extra != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, label, labelValues[i])
+		}
+		if extra != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extra, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// writeLabel writes a label pair with its value quoted and escaped.
+func writeLabel(w *bufio.Writer, label, value string) {
+	w.WriteString(label)
+	w.WriteString(`="`)
+	w.WriteString(labelValueEscaper.Replace(value))
+	w.WriteByte('"')
+}
+
+// labelValueEscaper escapes backslashes, double quotes and line feeds in label values.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeHelp escapes backslashes and line feeds in help texts.
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// formatFloat formats a value as the exposition format expects, with +Inf, -Inf and NaN spelled out.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
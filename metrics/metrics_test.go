@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.Counter("requests_total", "Requests by route.\nCounted once.", "route", "status")
+	requests.Inc("/tasks/{id}", "200")
+	requests.Add(2, "/tasks", "200")
+	requests.Inc("/tasks/{id}", "200")
+	assert.Same(t, requests, registry.Counter("requests_total", "", "route", "status"))
+
+	durations := registry.Histogram("duration_seconds", "Durations.", []float64{0.1, 1}, "route")
+	durations.Observe(0.05, "/tasks")
+	durations.Observe(0.1, "/tasks")
+	durations.Observe(3, "/tasks")
+
+	registry.GaugeFunc("tasks", "Tasks by status.", []string{"status"}, func(ctx context.Context) ([]Sample, error) {
+		return []Sample{{LabelValues: []string{"New"}, Value: 3}, {LabelValues: []string{`In "Progress"`}, Value: 1.5}}, nil
+	})
+
+	var out strings.Builder
+	assert.NoError(t, registry.Write(t.Context(), &out))
+	assert.Equal(t, `# HELP duration_seconds Durations.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/tasks",le="0.1"} 2
+duration_seconds_bucket{route="/tasks",le="1"} 2
+duration_seconds_bucket{route="/tasks",le="+Inf"} 3
+duration_seconds_sum{route="/tasks"} 3.15
+duration_seconds_count{route="/tasks"} 3
+# HELP requests_total Requests by route.\nCounted once.
+# TYPE requests_total counter
+requests_total{route="/tasks",status="200"} 2
+requests_total{route="/tasks/{id}",status="200"} 2
+# HELP tasks Tasks by status.
+# TYPE tasks gauge
+tasks{status="In \"Progress\""} 1.5
+tasks{status="New"} 3
+`, out.String())
+}
+
+func TestRegistry_FailingCollect(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("requests_total", "Requests.").Inc()
+	registry.GaugeFunc("tasks", "Tasks.", nil, func(ctx context.Context) ([]Sample, error) {
+		return nil, errors.New("database is down")
+	})
+
+	var out strings.Builder
+	err := registry.Write(t.Context(), &out)
+	assert.ErrorContains(t, err, "collecting tasks: database is down")
+	assert.Equal(t, "# HELP requests_total Requests.\n# TYPE requests_total counter\nrequests_total 1\n", out.String())
+}
+
+func TestRegistry_Conflicts(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("requests_total", "Requests.", "route")
+	assert.Panics(t, func() { registry.Counter("requests_total", "Requests.", "status") })
+	assert.Panics(t, func() { registry.Histogram("requests_total", "Requests.", DefaultBuckets, "route") })
+	assert.Panics(t, func() { registry.Counter("requests-total", "Requests.") })
+	assert.Panics(t, func() { registry.Counter("requests_total", "Requests.", "route").Inc() })
+}
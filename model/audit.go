@@ -0,0 +1,161 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AuditOperation is the kind of change an audit entry records.
+type AuditOperation string
+
+// Operations recorded in the audit trail.
+const (
+	AuditCreate  AuditOperation = "create"
+	AuditUpdate  AuditOperation = "update"
+	AuditDelete  AuditOperation = "delete"
+	AuditRestore AuditOperation = "restore"
+	AuditPurge   AuditOperation = "purge"
+)
+
+// AuditEntry records a single change of a task: who made it, when and what it changed.
+type AuditEntry struct {
+	// Id is a unique identifier for the entry
+	Id uint `gorm:"primaryKey"`
+	// TaskId is the id of the changed task
+	TaskId uint `gorm:"index;not null"`
+	// Actor is the name of whoever made the change
+	Actor string `gorm:"index;not null"`
+	// Operation is the kind of change
+	Operation AuditOperation `gorm:"not null"`
+	// Changes lists the fields the change affected with their values before and after it
+	Changes FieldChanges `gorm:"type:text"`
+	// At is the moment the change was made
+	At time.Time `gorm:"index;not null"`
+}
+
+// FieldChange is the value of a task field before and after a change.
+// Before is null for created tasks and After is null for deleted tasks.
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// FieldChanges are the field changes of an audit entry, stored as JSON text.
+type FieldChanges []FieldChange
+
+// Value encodes the changes as JSON for the database.
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		c = FieldChanges{}
+	}
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+// Scan decodes the changes from the JSON stored in the database.
+func (c *FieldChanges) Scan(value any) error {
+	switch data := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(data), c)
+	case []byte:
+		return json.Unmarshal(data, c)
+	default:
+		return errors.New("field changes must be stored as text")
+	}
+}
+
+// AuditQuery selects audit entries. Zero fields do not restrict the result.
+type AuditQuery struct {
+	// TaskId restricts the result to the entries of one task
+	TaskId uint
+	// Actor restricts the result to the changes made by one actor
+	Actor string
+	// From restricts the result to the changes made at or after this moment
+	From *time.Time
+	// To restricts the result to the changes made before this moment
+	To *time.Time
+	// Limit is the maximum number of entries to return, 0 returns all
+	Limit int
+	// Offset is the number of entries to skip
+	Offset int
+}
+
+// AuditPage is a single page of audit entries, most recent first.
+type AuditPage struct {
+	// Entries are the entries on this page
+	Entries []AuditEntry
+	// Total is the number of entries matching the query, ignoring Limit and Offset
+	Total int64
+	// Limit is the page size that was applied
+	Limit int
+	// Offset is the offset that was applied
+	Offset int
+}
+
+// auditedFields are the task fields recorded in audit entries, with their values as they appear in FieldChange.
+var auditedFields = []struct {
+	name  string
+	value func(Task) any
+}{
+	{"Name", func(t Task) any { return t.Name }},
+	{"Description", func(t Task) any { return t.Description }},
+	{"Status", func(t Task) any { return string(t.Status) }},
+	{"Priority", func(t Task) any {
+		if t.Priority == 0 {
+			return ""
+		}
+		return t.Priority.String()
+	}},
+	{"DueDate", func(t Task) any {
+		if t.DueDate == nil {
+			return nil
+		}
+		return t.DueDate.UTC().Format(time.RFC3339Nano)
+	}},
+	{"Assignee", func(t Task) any { return t.Assignee }},
+	{"ParentId", func(t Task) any { return optionalId(t.ParentId) }},
+	{"Recurrence", func(t Task) any { return t.Recurrence }},
+	{"TimeZone", func(t Task) any { return t.TimeZone }},
+	{"Occurrence", func(t Task) any { return t.Occurrence }},
+	{"NextOccurrenceId", func(t Task) any { return optionalId(t.NextOccurrenceId) }},
+}
+
+// DiffTasks returns the audited fields whose values differ between two versions of a task.
+// A nil before lists the fields of a created task and a nil after the fields of a deleted one,
+// leaving out fields that are empty.
+func DiffTasks(before, after *Task) FieldChanges {
+	changes := FieldChanges{}
+	for _, field := range auditedFields {
+		var beforeValue, afterValue any
+		if before != nil {
+			beforeValue = field.value(*before)
+		}
+		if after != nil {
+			afterValue = field.value(*after)
+		}
+		if beforeValue == afterValue || (before == nil && isEmpty(afterValue)) || (after == nil && isEmpty(beforeValue)) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: field.name, Before: beforeValue, After: afterValue})
+	}
+	return changes
+}
+
+// optionalId returns the id, or nil when there is none.
+func optionalId(id *uint) any {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+// isEmpty reports whether an audited value is null or an empty string.
+func isEmpty(value any) bool {
+	return value == nil || value == ""
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// ChecklistItem is a single step of the checklist embedded in a task.
+type ChecklistItem struct {
+	// Id is a unique identifier for the item
+	Id uint `gorm:"primaryKey"`
+	// TaskId is the id of the task the item belongs to
+	TaskId uint `gorm:"index;not null"`
+	// Text describes the step
+	Text string `gorm:"not null"`
+	// Done tells whether the step is finished
+	Done bool
+	// Position orders the items of a task, starting at 1
+	Position int
+	// CreatedAt is the moment the item was added
+	CreatedAt time.Time
+	// UpdatedAt is the moment the item was last changed
+	UpdatedAt time.Time
+}
+
+// Checklist is the ordered list of checklist items of a task.
+type Checklist struct {
+	// Items are the items in position order
+	Items []ChecklistItem
+	// Completion is the share of done items in percent, 0 for an empty checklist
+	Completion float64
+}
+
+// NewChecklist builds a checklist from items in position order and computes its completion.
+func NewChecklist(items []ChecklistItem) Checklist {
+	checklist := Checklist{Items: items}
+	if checklist.Items == nil {
+		checklist.Items = []ChecklistItem{}
+	}
+	if len(items) == 0 {
+		return checklist
+	}
+	done := 0
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+	checklist.Completion = float64(done) * 100 / float64(len(items))
+	return checklist
+}
+
+// TaskDetails is a task together with its checklist, as returned when a single task is requested.
+type TaskDetails struct {
+	Task
+	// Checklist is the checklist of the task
+	Checklist Checklist
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Comment is a message in the discussion thread of a task.
+type Comment struct {
+	// Id is a unique identifier for the comment
+	Id uint `gorm:"primaryKey"`
+	// TaskId is the id of the task the comment belongs to
+	TaskId uint `gorm:"index;not null"`
+	// Author is the name of the person who wrote the comment
+	Author string `gorm:"not null"`
+	// Body is the text of the comment
+	Body string `gorm:"not null"`
+	// Edited tells whether the body was changed after the comment was posted
+	Edited bool
+	// CreatedAt is the moment the comment was posted
+	CreatedAt time.Time
+	// UpdatedAt is the moment the comment was last changed
+	UpdatedAt time.Time
+}
+
+// CommentPage is a single page of the comments of a task, oldest first.
+type CommentPage struct {
+	// Comments are the comments on this page
+	Comments []Comment
+	// Total is the number of comments of the task
+	Total int64
+	// Limit is the page size that was applied
+	Limit int
+	// Offset is the offset that was applied
+	Offset int
+}
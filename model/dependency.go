@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// TaskDependency is an edge of the dependency graph: the task cannot start until the blocker is done.
+type TaskDependency struct {
+	// TaskId is the id of the blocked task
+	TaskId uint `gorm:"primaryKey;autoIncrement:false"`
+	// BlockerId is the id of the task that has to be finished first
+	BlockerId uint `gorm:"primaryKey;autoIncrement:false;index"`
+	// CreatedAt is the moment the dependency was added
+	CreatedAt time.Time
+}
+
+// DependencyGraph is the transitive set of blockers of a task.
+type DependencyGraph struct {
+	// TaskId is the id of the task the graph was built for
+	TaskId uint
+	// Tasks are the task itself and every task it transitively depends on
+	Tasks []Task
+	// Dependencies are the edges between Tasks
+	Dependencies []TaskDependency
+	// Order lists the ids of Tasks so that every blocker comes before the tasks it blocks
+	Order []uint
+}
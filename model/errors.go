@@ -0,0 +1,40 @@
+package model
+
+import "errors"
+
+// Kinds of domain errors. Errors returned by the repositories and services wrap one of them,
+// so that callers can tell what went wrong with errors.Is without knowing the storage behind them.
+var (
+	// ErrNotFound is the kind of errors reporting that a requested entity does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrValidation is the kind of errors reporting that input has invalid or inconsistent values.
+	ErrValidation = errors.New("validation failed")
+	// ErrConflict is the kind of errors reporting that a change clashes with the current state.
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed is the kind of errors reporting that a change was based on an outdated version of an entity.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// DomainError is an error of a given kind.
+// errors.Is reports true both for the error itself and for its kind.
+type DomainError struct {
+	// Kind is ErrNotFound, ErrValidation, ErrConflict or another sentinel callers can test for
+	Kind error
+	// Message describes the error
+	Message string
+}
+
+// NewError returns a DomainError of the given kind.
+func NewError(kind error, message string) error {
+	return &DomainError{Kind: kind, Message: message}
+}
+
+// Error returns the message of the error.
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is the kind of the error.
+func (e *DomainError) Is(target error) bool {
+	return target == e.Kind
+}
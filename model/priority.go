@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Priority is the urgency of a task. Higher values are more urgent,
+// so priorities can be compared and sorted numerically.
+type Priority int
+
+// Priorities a task can have. The zero value means that no priority was given.
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+	PriorityCritical
+)
+
+// Priorities lists every valid priority from least to most urgent.
+var Priorities = []Priority{PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical}
+
+// ErrUnknownPriority is returned when a value is not one of Priorities.
+var ErrUnknownPriority = NewError(ErrValidation, "unknown priority")
+
+var priorityNames = map[Priority]string{
+	PriorityLow:      "Low",
+	PriorityMedium:   "Medium",
+	PriorityHigh:     "High",
+	PriorityCritical: "Critical",
+}
+
+// IsValid reports whether p is one of Priorities.
+func (p Priority) IsValid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// ParsePriority returns the priority with exactly the given name.
+func ParsePriority(value string) (Priority, error) {
+	for priority, name := range priorityNames {
+		if name == value {
+			return priority, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownPriority, value)
+}
+
+// MarshalJSON encodes the priority by name, the zero Priority is encoded as an empty string.
+func (p Priority) MarshalJSON() ([]byte, error) {
+	if p == 0 {
+		return json.Marshal("")
+	}
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes a priority by name and rejects unknown values.
+// An empty string decodes to the zero Priority, meaning that no priority was given.
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		*p = 0
+		return nil
+	}
+	priority, err := ParsePriority(value)
+	if err != nil {
+		return err
+	}
+	*p = priority
+	return nil
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Status is the workflow state of a task.
+type Status string
+
+// Statuses a task can be in.
+const (
+	StatusNew        Status = "New"
+	StatusInProgress Status = "InProgress"
+	StatusBlocked    Status = "Blocked"
+	StatusDone       Status = "Done"
+	StatusCancelled  Status = "Cancelled"
+)
+
+// Statuses lists every valid status.
+var Statuses = []Status{StatusNew, StatusInProgress, StatusBlocked, StatusDone, StatusCancelled}
+
+// ErrUnknownStatus is returned when a value is not one of Statuses.
+var ErrUnknownStatus = NewError(ErrValidation, "unknown status")
+
+// legacyStatuses maps the free-form values stored before statuses were enforced to their status.
+// Keys are lower-cased with spaces, dashes and underscores removed.
+var legacyStatuses = map[string]Status{
+	"new":        StatusNew,
+	"pending":    StatusNew,
+	"todo":       StatusNew,
+	"open":       StatusNew,
+	"inprogress": StatusInProgress,
+	"started":    StatusInProgress,
+	"active":     StatusInProgress,
+	"blocked":    StatusBlocked,
+	"onhold":     StatusBlocked,
+	"waiting":    StatusBlocked,
+	"done":       StatusDone,
+	"completed":  StatusDone,
+	"complete":   StatusDone,
+	"finished":   StatusDone,
+	"closed":     StatusDone,
+	"cancelled":  StatusCancelled,
+	"canceled":   StatusCancelled,
+}
+
+// IsValid reports whether s is one of Statuses.
+func (s Status) IsValid() bool {
+	for _, status := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseStatus returns the status with exactly the given name.
+func ParseStatus(value string) (Status, error) {
+	status := Status(value)
+	if !status.IsValid() {
+		return "", fmt.Errorf("%w %q", ErrUnknownStatus, value)
+	}
+	return status, nil
+}
+
+// NormalizeStatus maps a legacy free-form value such as "pending" or "Completed" to a status.
+// Reports false when the value cannot be recognized.
+func NormalizeStatus(value string) (Status, bool) {
+	key := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(value))
+	status, ok := legacyStatuses[key]
+	return status, ok
+}
+
+// UnmarshalJSON decodes a status and rejects unknown values.
+// An empty string decodes to the zero Status, meaning that no status was given.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		*s = ""
+		return nil
+	}
+	status, err := ParseStatus(value)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
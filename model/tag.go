@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Tag is a label such as a team or component that tasks can be organized by.
+type Tag struct {
+	// Id is a unique identifier for the tag
+	Id uint `gorm:"primaryKey"`
+	// Name is the unique name of the tag
+	Name string `gorm:"uniqueIndex;not null"`
+	// CreatedAt is the moment the tag was created
+	CreatedAt time.Time
+}
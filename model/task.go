@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Task represents a task entity in the system.
 // Used for storing task information in the database.
@@ -9,8 +13,45 @@ type Task struct {
 	Id uint `gorm:"primaryKey"`
 	// Name is the title or name of the task
 	Name string
-	// Status represents the current state of the task (e.g., "Pending", "Completed")
-	Status string
+	// Description is a longer explanation of the task
+	Description string
+	// Status represents the current state of the task in its workflow
+	Status Status
+	// Priority is the urgency of the task
+	Priority Priority `gorm:"default:2"`
+	// DueDate is the moment the task should be finished by, if any
+	DueDate *time.Time
+	// Assignee is the person responsible for the task
+	Assignee string
+	// ParentId is the id of the task this task is a subtask of, if any
+	ParentId *uint `gorm:"index"`
+	// Recurrence is an RRULE such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10" making the task repeat, if any
+	Recurrence string
+	// TimeZone is the IANA time zone the recurrence is evaluated in, UTC when empty
+	TimeZone string
+	// Occurrence is the position of the task in its recurring series, starting at 1, maintained by the service
+	Occurrence int `gorm:"default:1"`
+	// NextOccurrenceId is the id of the task created when this occurrence was done, maintained by the service
+	NextOccurrenceId *uint
+	// Version counts the changes of the task, its checklist and its tags, starting at 1, and is incremented by the repository on every update
+	Version uint `gorm:"not null;default:1"`
+	// Tags are the labels attached to the task, maintained through the tag endpoints
+	Tags []Tag `gorm:"many2many:task_tags;"`
 	// Date is the creation timestamp of the task
 	Date time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	// CreatedAt is the moment the task was stored, maintained by the repository
+	CreatedAt time.Time
+	// UpdatedAt is the moment the task was last changed, maintained by the repository
+	UpdatedAt time.Time
+	// DeletedAt is the moment the task was moved to the trash, null for tasks that are not deleted
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// TaskNode is a task together with its subtasks, forming a subtree of the task hierarchy.
+type TaskNode struct {
+	Task
+	// Progress is the completion of the task in percent, computed from the statuses of its subtasks
+	Progress float64
+	// Children are the direct subtasks of the task
+	Children []TaskNode
 }
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TaskEventType is the kind of change a task event records.
+type TaskEventType string
+
+// Events recorded in the event log of a task.
+const (
+	// TaskCreated records a new task with all of its fields
+	TaskCreated TaskEventType = "TaskCreated"
+	// TaskRenamed records a change of the task name
+	TaskRenamed TaskEventType = "TaskRenamed"
+	// StatusChanged records a change of the task status
+	StatusChanged TaskEventType = "StatusChanged"
+	// TaskUpdated records a change of any other task fields
+	TaskUpdated TaskEventType = "TaskUpdated"
+	// TaskDeleted records moving the task to the trash
+	TaskDeleted TaskEventType = "TaskDeleted"
+	// TaskRestored records taking the task out of the trash
+	TaskRestored TaskEventType = "TaskRestored"
+	// TaskPurged records the permanent removal of the task
+	TaskPurged TaskEventType = "TaskPurged"
+)
+
+// TaskEvent is a single change in the append-only event log of a task.
+// The current state of a task is the result of applying its events in sequence.
+type TaskEvent struct {
+	// Id is a unique identifier for the event
+	Id uint `gorm:"primaryKey"`
+	// TaskId is the id of the changed task
+	TaskId uint `gorm:"uniqueIndex:idx_task_events_sequence;not null"`
+	// Sequence is the position of the event in the log of its task, starting at 1
+	Sequence uint `gorm:"uniqueIndex:idx_task_events_sequence;not null"`
+	// Type is the kind of change
+	Type TaskEventType `gorm:"not null"`
+	// Fields are the task fields set by the event, named like the fields of Task
+	Fields TaskEventFields `gorm:"type:text"`
+	// At is the moment the change was made
+	At time.Time `gorm:"index;not null"`
+}
+
+// TaskEventFields are the task fields set by an event with their new values, stored as JSON text.
+// Values are encoded like the fields of a Task in JSON.
+type TaskEventFields map[string]any
+
+// Value encodes the fields as JSON for the database.
+func (f TaskEventFields) Value() (driver.Value, error) {
+	if f == nil {
+		f = TaskEventFields{}
+	}
+	data, err := json.Marshal(f)
+	return string(data), err
+}
+
+// Scan decodes the fields from the JSON stored in the database.
+func (f *TaskEventFields) Scan(value any) error {
+	switch data := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(data), f)
+	case []byte:
+		return json.Unmarshal(data, f)
+	default:
+		return errors.New("task event fields must be stored as text")
+	}
+}
+
+// apply sets the fields of the event on task.
+func (e TaskEvent) apply(task *Task) error {
+	data, err := json.Marshal(e.Fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, task)
+}
+
+// ReplayTask applies the events of a task, oldest first, to rebuild the state they describe.
+// Returns false when the task no longer existed after the last event,
+// and an error when the events do not start with TaskCreated.
+func ReplayTask(events []TaskEvent) (Task, bool, error) {
+	if len(events) == 0 || events[0].Type != TaskCreated {
+		return Task{}, false, errors.New("event log of a task must start with " + string(TaskCreated))
+	}
+	var task Task
+	for _, event := range events {
+		if event.Type == TaskPurged {
+			return Task{}, false, nil
+		}
+		if err := event.apply(&task); err != nil {
+			return Task{}, false, fmt.Errorf("applying event %d of task %d: %w", event.Sequence, event.TaskId, err)
+		}
+	}
+	return task, true, nil
+}
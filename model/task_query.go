@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// Sort fields supported by TaskQuery.
+const (
+	SortById        = "id"
+	SortByName      = "name"
+	SortByStatus    = "status"
+	SortByDate      = "date"
+	SortByPriority  = "priority"
+	SortByDueDate   = "due_date"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+)
+
+// TaskSortFields lists every field a task listing can be sorted by.
+var TaskSortFields = []string{
+	SortById, SortByName, SortByStatus, SortByDate,
+	SortByPriority, SortByDueDate, SortByCreatedAt, SortByUpdatedAt,
+}
+
+// TaskQuery describes filtering, sorting and pagination of a task listing.
+type TaskQuery struct {
+	// Status restricts the result to tasks with exactly this status
+	Status Status
+	// NameContains restricts the result to tasks whose name contains this substring (case-insensitive)
+	NameContains string
+	// CreatedAfter restricts the result to tasks whose Date is after this moment
+	CreatedAfter *time.Time
+	// CreatedBefore restricts the result to tasks whose Date is before this moment
+	CreatedBefore *time.Time
+	// Priority restricts the result to tasks with exactly this priority
+	Priority Priority
+	// Assignee restricts the result to tasks assigned to this person
+	Assignee string
+	// DueAfter restricts the result to tasks due after this moment
+	DueAfter *time.Time
+	// DueBefore restricts the result to tasks due before this moment
+	DueBefore *time.Time
+	// Tags restricts the result to tasks carrying these tags, by name
+	Tags []string
+	// MatchAllTags requires a task to carry every one of Tags instead of any of them
+	MatchAllTags bool
+	// SortBy is the field the result is ordered by, one of TaskSortFields
+	SortBy string
+	// SortDesc reverses the sort order
+	SortDesc bool
+	// Limit is the maximum number of tasks in the result, 0 means no limit
+	Limit int
+	// Offset is the number of matching tasks skipped before the result starts
+	Offset int
+	// After continues a listing sorted by date right after the given position (keyset pagination)
+	After *TaskCursor
+}
+
+// TaskPage is a single page of tasks produced by a TaskQuery.
+type TaskPage struct {
+	// Tasks are the tasks on this page
+	Tasks []Task
+	// Total is the number of tasks matching the query regardless of pagination
+	Total int64
+	// Limit is the page size that was applied
+	Limit int
+	// Offset is the offset that was applied
+	Offset int
+	// NextCursor is the position to continue from when the listing is sorted by date and more tasks follow
+	NextCursor *TaskCursor
+}
+
+// ErrInvalidCursor is returned when a continuation token cannot be decoded.
+var ErrInvalidCursor = NewError(ErrValidation, "invalid cursor")
+
+// TaskCursor is a position in a listing sorted by (Date, Id).
+type TaskCursor struct {
+	// Date is the date of the last task that was returned
+	Date time.Time `json:"d"`
+	// Id is the id of the last task that was returned
+	Id uint `json:"i"`
+}
+
+// CursorAfter returns the cursor pointing right after task.
+func CursorAfter(task Task) *TaskCursor {
+	return &TaskCursor{Date: task.Date, Id: task.Id}
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c TaskCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTaskCursor parses a token produced by TaskCursor.Encode.
+func DecodeTaskCursor(token string) (TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+	var cursor TaskCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Id == 0 {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// IsTaskSortField reports whether field is one of TaskSortFields.
+func IsTaskSortField(field string) bool {
+	for _, f := range TaskSortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"task_manager_go/model"
+)
+
+var (
+	// ErrInvalidPatch is returned when a JSON Patch operation is malformed or refers to a path that does not exist.
+	ErrInvalidPatch = model.NewError(model.ErrValidation, "invalid patch")
+	// ErrTestFailed is returned when a JSON Patch test operation does not match the document.
+	ErrTestFailed = model.NewError(model.ErrConflict, "patch test failed")
+)
+
+// Operation is a single operation of an RFC 6902 JSON Patch.
+type Operation struct {
+	// Op is one of add, remove, replace, move, copy and test
+	Op string
+	// Path is the JSON Pointer the operation applies to
+	Path string
+	// From is the JSON Pointer a move or copy operation reads from
+	From string
+	// Value is the raw JSON value of an add, replace or test operation, nil when the member is missing
+	Value json.RawMessage
+}
+
+// UnmarshalJSON decodes an operation, ignoring members the operation does not define as RFC 6902 requires.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return fmt.Errorf("%w: operation must be an object", ErrInvalidPatch)
+	}
+	for name, target := range map[string]*string{"op": &o.Op, "path": &o.Path, "from": &o.From} {
+		if raw, ok := members[name]; ok {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return fmt.Errorf("%w: %q must be a string", ErrInvalidPatch, name)
+			}
+		}
+	}
+	o.Value = members["value"]
+	return nil
+}
+
+// Merge applies an RFC 7396 JSON Merge Patch to a document.
+// Both are values decoded by encoding/json into an any. Members set to null in the patch are removed.
+func Merge(document, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	documentObject, ok := document.(map[string]any)
+	if !ok {
+		documentObject = map[string]any{}
+	}
+	result := make(map[string]any, len(documentObject))
+	for name, value := range documentObject {
+		result[name] = value
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(result, name)
+		} else {
+			result[name] = Merge(result[name], value)
+		}
+	}
+	return result
+}
+
+// Apply applies the operations of an RFC 6902 JSON Patch to a document decoded by encoding/json into an any.
+// The operations are applied in order and the first failing one aborts the patch.
+// The document may be modified even when an error is returned.
+func Apply(document any, operations []Operation) (any, error) {
+	for i, operation := range operations {
+		var err error
+		document, err = apply(document, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+	return document, nil
+}
+
+// apply applies a single operation.
+func apply(document any, operation Operation) (any, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch operation.Op {
+	case "add":
+		value, err := operation.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "remove":
+		document, _, err := remove(document, path)
+		return document, err
+	case "replace":
+		value, err := operation.value()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := get(document, path); err != nil {
+			return nil, err
+		}
+		return set(document, path, value)
+	case "move", "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(document, from)
+		if err != nil {
+			return nil, err
+		}
+		if operation.Op == "copy" {
+			return add(document, path, deepCopy(value))
+		}
+		if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+			return nil, fmt.Errorf("%w: cannot move %q into itself", ErrInvalidPatch, operation.From)
+		}
+		document, _, err = remove(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "test":
+		expected, err := operation.value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			return nil, fmt.Errorf("%w: %s does not have the expected value", ErrTestFailed, operation.Path)
+		}
+		return document, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, operation.Op)
+	}
+}
+
+// value decodes the value of the operation.
+func (o Operation) value() (any, error) {
+	if o.Value == nil {
+		return nil, fmt.Errorf("%w: %s requires a value", ErrInvalidPatch, o.Op)
+	}
+	var value any
+	if err := json.Unmarshal(o.Value, &value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return value, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// get returns the value at path.
+func get(document any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := document.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, notFound(token)
+			}
+			document = value
+		case []any:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			document = node[index]
+		default:
+			return nil, notFound(token)
+		}
+	}
+	return document, nil
+}
+
+// add inserts value at path: it sets an object member or inserts into an array, "-" appending to it.
+func add(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(document, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			index := len(node)
+			if token != "-" {
+				var err error
+				if index, err = arrayIndex(token, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		default:
+			return nil, notFound(token)
+		}
+	})
+}
+
+// set replaces the existing value at path.
+func set(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(document, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			node[index] = value
+			return node, nil
+		default:
+			return nil, notFound(token)
+		}
+	})
+}
+
+// remove deletes the value at path and returns it.
+func remove(document any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: the whole document cannot be removed", ErrInvalidPatch)
+	}
+	var removed any
+	document, err := update(document, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, notFound(token)
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[index]
+			return append(node[:index], node[index+1:]...), nil
+		default:
+			return nil, notFound(token)
+		}
+	})
+	return document, removed, err
+}
+
+// update walks to the parent of the last token of path and replaces it with the result of change.
+// Arrays may be reallocated by change, so every parent on the way is updated with its new child.
+func update(document any, path []string, change func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return change(document, path[0])
+	}
+	child, err := get(document, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = update(child, path[1:], change)
+	if err != nil {
+		return nil, err
+	}
+	return set(document, path[:1], child)
+}
+
+// arrayIndex parses an array index token that must not exceed max.
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	return index, nil
+}
+
+// notFound returns the error for a path token that does not exist in the document.
+func notFound(token string) error {
+	return fmt.Errorf("%w: path element %q does not exist", ErrInvalidPatch, token)
+}
+
+// deepCopy copies a value decoded by encoding/json so that copies do not share objects or arrays.
+func deepCopy(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(node))
+		for name, child := range node {
+			result[name] = deepCopy(child)
+		}
+		return result
+	case []any:
+		result := make([]any, len(node))
+		for i, child := range node {
+			result[i] = deepCopy(child)
+		}
+		return result
+	default:
+		return value
+	}
+}
@@ -0,0 +1,113 @@
+package patch
+
+import (
+	"encoding/json"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decode(t *testing.T, data string) any {
+	var value any
+	assert.NoError(t, json.Unmarshal([]byte(data), &value))
+	return value
+}
+
+func TestMerge(t *testing.T) {
+	// examples from RFC 7396 appendix A
+	tests := []struct {
+		document string
+		patch    string
+		result   string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.patch, func(t *testing.T) {
+			assert.Equal(t, decode(t, tt.result), Merge(decode(t, tt.document), decode(t, tt.patch)))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	// examples from RFC 6902 appendix A
+	tests := []struct {
+		name     string
+		document string
+		patch    string
+		result   string
+	}{
+		{"add object member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"append array element", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc"]}]`, `{"foo":["bar",["abc"]]}`},
+		{"remove object member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace value", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`},
+		{"copy value", `{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"}]`, `{"foo":{"bar":1},"baz":{"bar":1}}`},
+		{"test then replace", `{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2},{"op":"replace","path":"/baz","value":null}]`,
+			`{"baz":null,"foo":["a",2,"c"]}`},
+		{"escaped path", `{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+		{"ignore unknown members", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"baz":"qux","foo":"bar"}`},
+		{"replace whole document", `{"foo":"bar"}`, `[{"op":"replace","path":"","value":{"baz":"qux"}}]`, `{"baz":"qux"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []Operation
+			assert.NoError(t, json.Unmarshal([]byte(tt.patch), &operations))
+			result, err := Apply(decode(t, tt.document), operations)
+			assert.NoError(t, err)
+			assert.Equal(t, decode(t, tt.result), result)
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		kind  error
+	}{
+		{"missing member", `[{"op":"remove","path":"/baz"}]`, model.ErrValidation},
+		{"missing parent", `[{"op":"add","path":"/baz/bat","value":"qux"}]`, model.ErrValidation},
+		{"replace missing member", `[{"op":"replace","path":"/baz","value":1}]`, model.ErrValidation},
+		{"array index out of range", `[{"op":"add","path":"/list/5","value":1}]`, model.ErrValidation},
+		{"leading zero index", `[{"op":"remove","path":"/list/01"}]`, model.ErrValidation},
+		{"missing value", `[{"op":"add","path":"/baz"}]`, model.ErrValidation},
+		{"relative path", `[{"op":"add","path":"baz","value":1}]`, model.ErrValidation},
+		{"unknown op", `[{"op":"merge","path":"/foo","value":1}]`, model.ErrValidation},
+		{"move into itself", `[{"op":"move","from":"/list","path":"/list/0"}]`, model.ErrValidation},
+		{"failing test", `[{"op":"test","path":"/foo","value":"baz"}]`, model.ErrConflict},
+		{"number is not a string", `[{"op":"test","path":"/list/0","value":"1"}]`, model.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []Operation
+			assert.NoError(t, json.Unmarshal([]byte(tt.patch), &operations))
+			_, err := Apply(decode(t, `{"foo":"bar","list":[1,2]}`), operations)
+			assert.ErrorIs(t, err, tt.kind)
+		})
+	}
+
+	var operations []Operation
+	assert.ErrorIs(t, json.Unmarshal([]byte(`[{"op":1,"path":"/foo"}]`), &operations), ErrInvalidPatch)
+}
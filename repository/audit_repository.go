@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"task_manager_go/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepositoryInterface defines the contract for storing the audit trail of tasks.
+type AuditRepositoryInterface interface {
+	// Record stores a new audit entry.
+	Record(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error)
+	// Find retrieves a page of the audit entries matching a query, most recent first.
+	Find(ctx context.Context, query model.AuditQuery) (model.AuditPage, error)
+}
+
+// AuditRepository implements AuditRepositoryInterface using GORM for database operations.
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new instance of AuditRepository with the specified database connection.
+func NewAuditRepository(db *gorm.DB) AuditRepositoryInterface {
+	return &AuditRepository{db: db}
+}
+
+// Record implements the creation of an audit entry in the database.
+func (r *AuditRepository) Record(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error) {
+	result := r.db.WithContext(ctx).Create(&entry)
+	return entry, result.Error
+}
+
+// Find implements the retrieval of a page of audit entries from the database.
+// The total count ignores Limit and Offset.
+func (r *AuditRepository) Find(ctx context.Context, query model.AuditQuery) (model.AuditPage, error) {
+	page := model.AuditPage{Limit: query.Limit, Offset: query.Offset}
+	if err := r.db.WithContext(ctx).Model(&model.AuditEntry{}).Scopes(auditFilters(query)).Count(&page.Total).Error; err != nil {
+		return model.AuditPage{}, err
+	}
+	db := r.db.WithContext(ctx).Scopes(auditFilters(query)).Order("at DESC").Order("id DESC").Offset(query.Offset)
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	result := db.Find(&page.Entries)
+	return page, result.Error
+}
+
+// auditFilters returns a scope restricting a query to the audit entries matching query.
+func auditFilters(query model.AuditQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.TaskId != 0 {
+			db = db.Where("task_id = ?", query.TaskId)
+		}
+		if query.Actor != "" {
+			db = db.Where("actor = ?", query.Actor)
+		}
+		if query.From != nil {
+			db = db.Where("at >= ?", *query.From)
+		}
+		if query.To != nil {
+			db = db.Where("at < ?", *query.To)
+		}
+		return db
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"task_manager_go/config"
+	"task_manager_go/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_Find(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewAuditRepository(db)
+
+	start := time.Now().Truncate(time.Millisecond)
+	entries := []model.AuditEntry{
+		{TaskId: 1, Actor: "alex", Operation: model.AuditCreate, At: start,
+			Changes: model.FieldChanges{{Field: "Name", After: "Task"}}},
+		{TaskId: 1, Actor: "maria", Operation: model.AuditUpdate, At: start.Add(time.Minute),
+			Changes: model.FieldChanges{{Field: "Name", Before: "Task", After: "Renamed"}}},
+		{TaskId: 2, Actor: "alex", Operation: model.AuditDelete, At: start.Add(2 * time.Minute)},
+	}
+	for _, entry := range entries {
+		_, err := repo.Record(t.Context(), entry)
+		assert.NoError(t, err)
+	}
+
+	page, err := repo.Find(t.Context(), model.AuditQuery{TaskId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, model.AuditUpdate, page.Entries[0].Operation)
+	assert.Equal(t, model.FieldChanges{{Field: "Name", Before: "Task", After: "Renamed"}}, page.Entries[0].Changes)
+
+	to := start.Add(2 * time.Minute)
+	page, err = repo.Find(t.Context(), model.AuditQuery{Actor: "alex", From: &start, To: &to})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, model.AuditCreate, page.Entries[0].Operation)
+
+	page, err = repo.Find(t.Context(), model.AuditQuery{Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Len(t, page.Entries, 1)
+	assert.Equal(t, "maria", page.Entries[0].Actor)
+}
+
+func TestTransactor_RollsBack(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	failure := errors.New("failure")
+
+	err := NewTransactor(db, NewTaskRepository).InTransaction(t.Context(), func(repos Repositories) error {
+		task, err := repos.Tasks.CreateTask(t.Context(), model.Task{Name: "Task", Status: model.StatusNew})
+		assert.NoError(t, err)
+		_, err = repos.Audit.Record(t.Context(), model.AuditEntry{TaskId: task.Id, Actor: "alex", Operation: model.AuditCreate, At: time.Now()})
+		assert.NoError(t, err)
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	tasks, err := NewTaskRepository(db).GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+	page, err := NewAuditRepository(db).Find(t.Context(), model.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Zero(t, page.Total)
+}
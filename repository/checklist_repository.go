@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"task_manager_go/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrChecklistMismatch is returned when a new checklist order does not list exactly the items of the task.
+var ErrChecklistMismatch = model.NewError(model.ErrValidation, "order must list every checklist item of the task exactly once")
+
+// ChecklistRepositoryInterface defines the contract for storing the checklist items of tasks.
+type ChecklistRepositoryInterface interface {
+	// AddItem stores a new item at the end of the checklist of its task.
+	AddItem(ctx context.Context, item model.ChecklistItem) (model.ChecklistItem, error)
+	// FindByTask retrieves the checklist items of a task in position order.
+	FindByTask(ctx context.Context, taskId uint) ([]model.ChecklistItem, error)
+	// FindById retrieves a checklist item by its ID.
+	FindById(ctx context.Context, id uint) (model.ChecklistItem, error)
+	// ToggleItem flips the done flag of an item in a single atomic step.
+	ToggleItem(ctx context.Context, id uint) (model.ChecklistItem, error)
+	// Reorder assigns new positions to all items of a task at once, in the order of itemIds.
+	Reorder(ctx context.Context, taskId uint, itemIds []uint) ([]model.ChecklistItem, error)
+	// DeleteItem removes a checklist item by its ID.
+	DeleteItem(ctx context.Context, id uint) error
+	// DeleteByTask removes every checklist item of a task.
+	DeleteByTask(ctx context.Context, taskId uint) error
+}
+
+// ChecklistRepository implements ChecklistRepositoryInterface using GORM for database operations.
+type ChecklistRepository struct {
+	db *gorm.DB
+}
+
+// NewChecklistRepository creates a new instance of ChecklistRepository with the specified database connection.
+func NewChecklistRepository(db *gorm.DB) ChecklistRepositoryInterface {
+	return &ChecklistRepository{db: db}
+}
+
+// AddItem implements appending an item to a checklist in the database.
+// The position is computed in the same transaction as the insert, with the task locked,
+// so that concurrent adds and reorders of the same checklist wait for each other.
+func (r *ChecklistRepository) AddItem(ctx context.Context, item model.ChecklistItem) (model.ChecklistItem, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockTask(tx, item.TaskId); err != nil {
+			return err
+		}
+		var last int
+		err := tx.Model(&model.ChecklistItem{}).
+			Where("task_id = ?", item.TaskId).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		item.Position = last + 1
+		return tx.Create(&item).Error
+	})
+	return item, err
+}
+
+// FindByTask implements the retrieval of the checklist of a task from the database.
+func (r *ChecklistRepository) FindByTask(ctx context.Context, taskId uint) ([]model.ChecklistItem, error) {
+	var items []model.ChecklistItem
+	result := r.db.WithContext(ctx).Where("task_id = ?", taskId).Order("position, id").Find(&items)
+	return items, result.Error
+}
+
+// FindById implements the retrieval of a checklist item by its ID from the database.
+func (r *ChecklistRepository) FindById(ctx context.Context, id uint) (model.ChecklistItem, error) {
+	var item model.ChecklistItem
+	result := r.db.WithContext(ctx).First(&item, id)
+	return item, translateError(result.Error, "checklist item")
+}
+
+// ToggleItem implements flipping the done flag in the database.
+// The flag is negated by the UPDATE statement itself, so concurrent toggles never overwrite each other.
+func (r *ChecklistRepository) ToggleItem(ctx context.Context, id uint) (model.ChecklistItem, error) {
+	result := r.db.WithContext(ctx).Model(&model.ChecklistItem{}).Where("id = ?", id).Update("done", gorm.Expr("NOT done"))
+	if result.Error != nil {
+		return model.ChecklistItem{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.ChecklistItem{}, notFound("checklist item")
+	}
+	return r.FindById(ctx, id)
+}
+
+// Reorder implements the reordering of a checklist in a single transaction.
+// The task is locked like in AddItem, so that no item is added or reordered while the order is checked and stored.
+func (r *ChecklistRepository) Reorder(ctx context.Context, taskId uint, itemIds []uint) ([]model.ChecklistItem, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockTask(tx, taskId); err != nil {
+			return err
+		}
+		var items []model.ChecklistItem
+		err := tx.Where("task_id = ?", taskId).Find(&items).Error
+		if err != nil {
+			return err
+		}
+		if !sameItems(items, itemIds) {
+			return ErrChecklistMismatch
+		}
+		for i, id := range itemIds {
+			err := tx.Model(&model.ChecklistItem{}).Where("id = ?", id).Update("position", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByTask(ctx, taskId)
+}
+
+// DeleteItem implements the removal of a checklist item from the database by its ID.
+func (r *ChecklistRepository) DeleteItem(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.ChecklistItem{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notFound("checklist item")
+	}
+	return nil
+}
+
+// DeleteByTask implements the removal of the whole checklist of a task from the database.
+func (r *ChecklistRepository) DeleteByTask(ctx context.Context, taskId uint) error {
+	result := r.db.WithContext(ctx).Where("task_id = ?", taskId).Delete(&model.ChecklistItem{})
+	return result.Error
+}
+
+// lockTask locks the row of a task until the end of the transaction of tx,
+// serializing the changes of its checklist. SQLite, which has no row locks, serializes all writes anyway.
+func lockTask(tx *gorm.DB, taskId uint) error {
+	var task model.Task
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&task, taskId).Error
+	return translateError(err, "task")
+}
+
+// sameItems reports whether ids lists every item exactly once.
+func sameItems(items []model.ChecklistItem, ids []uint) bool {
+	if len(items) != len(ids) {
+		return false
+	}
+	remaining := make(map[uint]bool, len(items))
+	for _, item := range items {
+		remaining[item.Id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
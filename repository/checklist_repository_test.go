@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"sync"
+	"task_manager_go/config"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklistRepository(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewChecklistRepository(db)
+	task, err := NewTaskRepository(db).CreateTask(t.Context(), model.Task{Name: "checklist", Status: model.StatusNew})
+	assert.NoError(t, err)
+
+	var ids []uint
+	for _, text := range []string{"first", "second", "third"} {
+		item, err := repo.AddItem(t.Context(), model.ChecklistItem{TaskId: task.Id, Text: text})
+		assert.NoError(t, err)
+		ids = append(ids, item.Id)
+	}
+
+	items, err := repo.Reorder(t.Context(), task.Id, []uint{ids[2], ids[0], ids[1]})
+	assert.NoError(t, err)
+	assert.Equal(t, "third", items[0].Text)
+	assert.Equal(t, 1, items[0].Position)
+
+	_, err = repo.Reorder(t.Context(), task.Id, []uint{ids[0]})
+	assert.ErrorIs(t, err, ErrChecklistMismatch)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.ToggleItem(t.Context(), ids[0])
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	item, err := repo.FindById(t.Context(), ids[0])
+	assert.NoError(t, err)
+	assert.False(t, item.Done)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.AddItem(t.Context(), model.ChecklistItem{TaskId: task.Id, Text: "concurrent"})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	items, err = repo.FindByTask(t.Context(), task.Id)
+	assert.NoError(t, err)
+	positions := make(map[int]bool)
+	for _, item := range items {
+		positions[item.Position] = true
+	}
+	assert.Len(t, positions, len(items))
+
+	_, err = repo.AddItem(t.Context(), model.ChecklistItem{TaskId: task.Id + 1, Text: "orphan"})
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	assert.NoError(t, repo.DeleteItem(t.Context(), ids[1]))
+	assert.NoError(t, repo.DeleteByTask(t.Context(), task.Id))
+	items, err = repo.FindByTask(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"task_manager_go/model"
+
+	"gorm.io/gorm"
+)
+
+// CommentRepositoryInterface defines the contract for storing the comments of tasks.
+type CommentRepositoryInterface interface {
+	// CreateComment stores a new comment.
+	CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error)
+	// FindByTask retrieves a page of the comments of a task, oldest first.
+	FindByTask(ctx context.Context, taskId uint, limit, offset int) (model.CommentPage, error)
+	// FindById retrieves a comment by its ID.
+	FindById(ctx context.Context, id uint) (model.Comment, error)
+	// UpdateComment replaces the body of a comment and marks it as edited.
+	UpdateComment(ctx context.Context, id uint, body string) (model.Comment, error)
+	// DeleteComment removes a comment by its ID.
+	DeleteComment(ctx context.Context, id uint) error
+	// DeleteByTask removes every comment of a task.
+	DeleteByTask(ctx context.Context, taskId uint) error
+}
+
+// CommentRepository implements CommentRepositoryInterface using GORM for database operations.
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository creates a new instance of CommentRepository with the specified database connection.
+func NewCommentRepository(db *gorm.DB) CommentRepositoryInterface {
+	return &CommentRepository{db: db}
+}
+
+// CreateComment implements the creation of a new comment in the database.
+func (r *CommentRepository) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	result := r.db.WithContext(ctx).Create(&comment)
+	return comment, result.Error
+}
+
+// FindByTask implements the retrieval of a page of the comments of a task from the database.
+func (r *CommentRepository) FindByTask(ctx context.Context, taskId uint, limit, offset int) (model.CommentPage, error) {
+	page := model.CommentPage{Limit: limit, Offset: offset}
+	if err := r.db.WithContext(ctx).Model(&model.Comment{}).Where("task_id = ?", taskId).Count(&page.Total).Error; err != nil {
+		return model.CommentPage{}, err
+	}
+	db := r.db.WithContext(ctx).Where("task_id = ?", taskId).Order("created_at, id").Offset(offset)
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	result := db.Find(&page.Comments)
+	return page, result.Error
+}
+
+// FindById implements the retrieval of a comment by its ID from the database.
+func (r *CommentRepository) FindById(ctx context.Context, id uint) (model.Comment, error) {
+	var comment model.Comment
+	result := r.db.WithContext(ctx).First(&comment, id)
+	return comment, translateError(result.Error, "comment")
+}
+
+// UpdateComment implements the update of the body of a comment in the database.
+func (r *CommentRepository) UpdateComment(ctx context.Context, id uint, body string) (model.Comment, error) {
+	result := r.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"body":   body,
+		"edited": true,
+	})
+	if result.Error != nil {
+		return model.Comment{}, result.Error
+	}
+	return r.FindById(ctx, id)
+}
+
+// DeleteComment implements the removal of a comment from the database by its ID.
+func (r *CommentRepository) DeleteComment(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.Comment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notFound("comment")
+	}
+	return nil
+}
+
+// DeleteByTask implements the removal of every comment of a task from the database.
+func (r *CommentRepository) DeleteByTask(ctx context.Context, taskId uint) error {
+	result := r.db.WithContext(ctx).Where("task_id = ?", taskId).Delete(&model.Comment{})
+	return result.Error
+}
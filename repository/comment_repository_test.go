@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"task_manager_go/config"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentRepository(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewCommentRepository(db)
+
+	first, err := repo.CreateComment(t.Context(), model.Comment{TaskId: 1, Author: "alex", Body: "first"})
+	assert.NoError(t, err)
+	assert.NotZero(t, first.Id)
+	_, err = repo.CreateComment(t.Context(), model.Comment{TaskId: 1, Author: "kim", Body: "second"})
+	assert.NoError(t, err)
+	_, err = repo.CreateComment(t.Context(), model.Comment{TaskId: 2, Author: "kim", Body: "other task"})
+	assert.NoError(t, err)
+
+	page, err := repo.FindByTask(t.Context(), 1, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, "second", page.Comments[0].Body)
+
+	updated, err := repo.UpdateComment(t.Context(), first.Id, "first, edited")
+	assert.NoError(t, err)
+	assert.True(t, updated.Edited)
+	assert.Equal(t, "first, edited", updated.Body)
+
+	assert.NoError(t, repo.DeleteComment(t.Context(), first.Id))
+	assert.Error(t, repo.DeleteComment(t.Context(), first.Id))
+
+	assert.NoError(t, repo.DeleteByTask(t.Context(), 1))
+	page, err = repo.FindByTask(t.Context(), 1, 0, 0)
+	assert.NoError(t, err)
+	assert.Zero(t, page.Total)
+}
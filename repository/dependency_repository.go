@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"task_manager_go/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DependencyRepositoryInterface defines the contract for storing dependencies between tasks.
+type DependencyRepositoryInterface interface {
+	// AddDependency stores a dependency, adding an existing dependency again has no effect.
+	AddDependency(ctx context.Context, dependency model.TaskDependency) (model.TaskDependency, error)
+	// RemoveDependency removes the dependency of a task on a blocker.
+	RemoveDependency(ctx context.Context, taskId, blockerId uint) error
+	// FindBlockers retrieves the ids of the tasks a task directly depends on.
+	FindBlockers(ctx context.Context, taskId uint) ([]uint, error)
+	// DeleteByTask removes every dependency a task takes part in, as blocked task or as blocker.
+	DeleteByTask(ctx context.Context, taskId uint) error
+}
+
+// DependencyRepository implements DependencyRepositoryInterface using GORM for database operations.
+type DependencyRepository struct {
+	db *gorm.DB
+}
+
+// NewDependencyRepository creates a new instance of DependencyRepository with the specified database connection.
+func NewDependencyRepository(db *gorm.DB) DependencyRepositoryInterface {
+	return &DependencyRepository{db: db}
+}
+
+// AddDependency implements the creation of a dependency in the database.
+func (r *DependencyRepository) AddDependency(ctx context.Context, dependency model.TaskDependency) (model.TaskDependency, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&dependency)
+	return dependency, result.Error
+}
+
+// RemoveDependency implements the removal of a dependency from the database.
+// Returns a model.ErrNotFound error when the dependency does not exist.
+func (r *DependencyRepository) RemoveDependency(ctx context.Context, taskId, blockerId uint) error {
+	result := r.db.WithContext(ctx).Where("task_id = ? AND blocker_id = ?", taskId, blockerId).Delete(&model.TaskDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notFound("dependency")
+	}
+	return nil
+}
+
+// FindBlockers implements the retrieval of the direct blockers of a task ordered by id.
+func (r *DependencyRepository) FindBlockers(ctx context.Context, taskId uint) ([]uint, error) {
+	var ids []uint
+	result := r.db.WithContext(ctx).Model(&model.TaskDependency{}).Where("task_id = ?", taskId).Order("blocker_id").Pluck("blocker_id", &ids)
+	return ids, result.Error
+}
+
+// DeleteByTask implements the removal of every dependency of a task from the database.
+func (r *DependencyRepository) DeleteByTask(ctx context.Context, taskId uint) error {
+	result := r.db.WithContext(ctx).Where("task_id = ? OR blocker_id = ?", taskId, taskId).Delete(&model.TaskDependency{})
+	return result.Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"task_manager_go/config"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyRepository(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewDependencyRepository(db)
+
+	_, err := repo.AddDependency(t.Context(), model.TaskDependency{TaskId: 1, BlockerId: 3})
+	assert.NoError(t, err)
+	_, err = repo.AddDependency(t.Context(), model.TaskDependency{TaskId: 1, BlockerId: 2})
+	assert.NoError(t, err)
+	_, err = repo.AddDependency(t.Context(), model.TaskDependency{TaskId: 1, BlockerId: 2})
+	assert.NoError(t, err)
+	_, err = repo.AddDependency(t.Context(), model.TaskDependency{TaskId: 2, BlockerId: 3})
+	assert.NoError(t, err)
+
+	blockers, err := repo.FindBlockers(t.Context(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2, 3}, blockers)
+
+	assert.NoError(t, repo.RemoveDependency(t.Context(), 1, 2))
+	assert.Error(t, repo.RemoveDependency(t.Context(), 1, 2))
+
+	assert.NoError(t, repo.DeleteByTask(t.Context(), 3))
+	blockers, err = repo.FindBlockers(t.Context(), 2)
+	assert.NoError(t, err)
+	assert.Empty(t, blockers)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+	"task_manager_go/model"
+
+	"gorm.io/gorm"
+)
+
+// errVersionMismatch is returned when a task was changed after the version an update is based on.
+var errVersionMismatch = model.NewError(model.ErrPreconditionFailed, "task has been changed by another request")
+
+// notFound returns the error reported when the named entity does not exist.
+func notFound(entity string) error {
+	return model.NewError(model.ErrNotFound, entity+" wasn't found")
+}
+
+// translateError maps the errors of GORM to the error kinds of the model package,
+// so that GORM errors such as gorm.ErrRecordNotFound do not leak out of the repositories.
+// entity names the record the operation was about.
+func translateError(err error, entity string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return notFound(entity)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return model.NewError(model.ErrConflict, entity+" already exists")
+	default:
+		return err
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !(unix && !aix) && !windows
+
+package repository
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile fails, because there is no file lock on this platform
+// that would keep two processes from writing the same file.
+func lockFile(*os.File) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"task_manager_go/model"
+)
+
+// compactMinRecords is the number of records the log of a FileStore needs before it is compacted.
+const compactMinRecords = 1000
+
+// errFileLocked is returned when the file of a FileStore is already opened by another process.
+var errFileLocked = errors.New("task file is used by another process")
+
+// FileStore keeps tasks and everything attached to them, tags, comments, checklists, dependencies
+// and the audit trail, in a single local file, for deployments without a database server.
+// It serves the data from memory like a MemoryStore and runs transactions the same way.
+// Every write, and every transaction as a whole, is appended to the file as JSON lines and synced
+// to disk before it is applied, so an acknowledged change survives a crash and a transaction
+// is never found half-written. Once the log holds more than twice as many records
+// as there are stored values, it is compacted to one record per value and atomically replaces the file.
+// A lock file next to the data file keeps other processes from opening it at the same time.
+type FileStore struct {
+	*MemoryStore
+	path string
+	lock *os.File
+	log  *os.File
+	// records counts the records in the log
+	records int
+	// dirty is set when an append failed and may have left part of a record behind,
+	// so the file is compacted before the next one
+	dirty bool
+}
+
+// fileRecord is a line in the file of a FileStore.
+// A record stores or removes a single value or, at the start of a compacted file, sets the next ids,
+// so that the ids of removed values are not given out again.
+type fileRecord struct {
+	NextId              uint `json:",omitempty"`
+	NextTagId           uint `json:",omitempty"`
+	NextCommentId       uint `json:",omitempty"`
+	NextChecklistItemId uint `json:",omitempty"`
+
+	Task                 *model.Task           `json:",omitempty"`
+	Purged               uint                  `json:",omitempty"`
+	Tag                  *model.Tag            `json:",omitempty"`
+	DeletedTag           uint                  `json:",omitempty"`
+	Comment              *model.Comment        `json:",omitempty"`
+	DeletedComment       uint                  `json:",omitempty"`
+	ChecklistItem        *model.ChecklistItem  `json:",omitempty"`
+	DeletedChecklistItem uint                  `json:",omitempty"`
+	Dependency           *model.TaskDependency `json:",omitempty"`
+	RemovedDependency    *model.TaskDependency `json:",omitempty"`
+	AuditEntry           *model.AuditEntry     `json:",omitempty"`
+}
+
+// OpenFileStore opens the file at path, creating it if it does not exist, and loads its content.
+// A record cut off by a crash at the end of the file is dropped.
+// Returns an error if another process has the file open or the file is corrupt.
+func OpenFileStore(path string) (*FileStore, error) {
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(lock); err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+
+	s := &FileStore{MemoryStore: NewMemoryStore(NewMemoryTaskRepository()), path: path, lock: lock}
+	torn, err := s.load()
+	if err == nil && (torn || s.needsCompaction()) {
+		err = s.compact()
+	}
+	if err == nil && s.log == nil {
+		s.log, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.Tasks.journal = s.append
+	s.Audit.journal = s.append
+	s.Dependencies.journal = s.append
+	s.Comments.journal = s.append
+	s.Checklists.journal = s.append
+	s.MemoryStore.prepare = s.compactIfNeeded
+	s.MemoryStore.journal = s.write
+	return s, nil
+}
+
+// Close closes the file and releases its lock. The repositories must not be used afterwards.
+func (s *FileStore) Close() error {
+	s.Tasks.mu.Lock()
+	defer s.Tasks.mu.Unlock()
+	var err error
+	if s.log != nil {
+		err = s.log.Close()
+		s.log = nil
+	}
+	return errors.Join(err, s.lock.Close())
+}
+
+// load replays the records of the file into memory.
+// Reports whether the last record was cut off, which is only expected after a crash while appending.
+func (s *FileStore) load() (torn bool, err error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// a complete record always ends with a newline
+			return len(data) > 0, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		var record fileRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return false, fmt.Errorf("%s:%d: %w", s.path, line, err)
+		}
+		s.replay(record)
+	}
+}
+
+// replay applies a record read from the file.
+func (s *FileStore) replay(record fileRecord) {
+	s.records++
+	tasks, tags, comments, checklists := s.Tasks, s.Tags, s.Comments, s.Checklists
+	tasks.nextId = max(tasks.nextId, record.NextId)
+	tags.nextId = max(tags.nextId, record.NextTagId)
+	comments.nextId = max(comments.nextId, record.NextCommentId)
+	checklists.nextId = max(checklists.nextId, record.NextChecklistItemId)
+	switch {
+	case record.Task != nil:
+		tasks.tasks[record.Task.Id] = *record.Task
+		tasks.nextId = max(tasks.nextId, record.Task.Id+1)
+	case record.Purged != 0:
+		delete(tasks.tasks, record.Purged)
+		tasks.nextId = max(tasks.nextId, record.Purged+1)
+	case record.Tag != nil:
+		tags.tags[record.Tag.Id] = *record.Tag
+		tags.nextId = max(tags.nextId, record.Tag.Id+1)
+	case record.DeletedTag != 0:
+		delete(tags.tags, record.DeletedTag)
+		tags.nextId = max(tags.nextId, record.DeletedTag+1)
+	case record.Comment != nil:
+		comments.comments[record.Comment.Id] = *record.Comment
+		comments.nextId = max(comments.nextId, record.Comment.Id+1)
+	case record.DeletedComment != 0:
+		delete(comments.comments, record.DeletedComment)
+		comments.nextId = max(comments.nextId, record.DeletedComment+1)
+	case record.ChecklistItem != nil:
+		checklists.items[record.ChecklistItem.Id] = *record.ChecklistItem
+		checklists.nextId = max(checklists.nextId, record.ChecklistItem.Id+1)
+	case record.DeletedChecklistItem != 0:
+		delete(checklists.items, record.DeletedChecklistItem)
+		checklists.nextId = max(checklists.nextId, record.DeletedChecklistItem+1)
+	case record.Dependency != nil:
+		s.Dependencies.dependencies[dependencyKey{record.Dependency.TaskId, record.Dependency.BlockerId}] = *record.Dependency
+	case record.RemovedDependency != nil:
+		delete(s.Dependencies.dependencies, dependencyKey{record.RemovedDependency.TaskId, record.RemovedDependency.BlockerId})
+	case record.AuditEntry != nil:
+		s.Audit.entries = append(s.Audit.entries, *record.AuditEntry)
+	}
+}
+
+// append writes the records of a change to the end of the file and syncs it,
+// compacting the file first if the log has grown too long. The caller must hold the write lock.
+func (s *FileStore) append(records []fileRecord) error {
+	if err := s.compactIfNeeded(); err != nil {
+		return err
+	}
+	return s.write(records)
+}
+
+// compactIfNeeded compacts the file if the log has grown too long or a failed append may have left
+// part of a record behind. The caller must hold the write lock and not have applied a pending change yet,
+// since the compacted file holds the values in memory.
+func (s *FileStore) compactIfNeeded() error {
+	if s.dirty || s.needsCompaction() {
+		return s.compact()
+	}
+	return nil
+}
+
+// write appends records to the end of the file in one write and syncs it. The caller must hold the write lock.
+func (s *FileStore) write(records []fileRecord) error {
+	if s.log == nil {
+		return errors.New("task file is closed")
+	}
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	if _, err := s.log.Write(data.Bytes()); err != nil {
+		s.dirty = true
+		return err
+	}
+	if err := s.log.Sync(); err != nil {
+		s.dirty = true
+		return err
+	}
+	s.records += len(records)
+	return nil
+}
+
+// needsCompaction reports whether the log holds enough superseded records to be worth compacting.
+func (s *FileStore) needsCompaction() bool {
+	return s.records >= compactMinRecords && s.records > 2*s.values()
+}
+
+// values counts the values the store holds, which is the number of records of a compacted file.
+// The caller must hold a lock.
+func (s *FileStore) values() int {
+	return len(s.Tasks.tasks) + len(s.Tags.tags) + len(s.Comments.comments) + len(s.Checklists.items) +
+		len(s.Dependencies.dependencies) + len(s.Audit.entries)
+}
+
+// compact replaces the file with one record per value and reopens it for appending.
+// The caller must hold the write lock.
+func (s *FileStore) compact() error {
+	records := []fileRecord{{
+		NextId:              s.Tasks.nextId,
+		NextTagId:           s.Tags.nextId,
+		NextCommentId:       s.Comments.nextId,
+		NextChecklistItemId: s.Checklists.nextId,
+	}}
+	for _, task := range s.Tasks.filter(func(model.Task) bool { return true }) {
+		records = append(records, fileRecord{Task: &task})
+	}
+	for _, tag := range sortedValues(s.Tags.tags) {
+		records = append(records, fileRecord{Tag: &tag})
+	}
+	for _, comment := range sortedValues(s.Comments.comments) {
+		records = append(records, fileRecord{Comment: &comment})
+	}
+	for _, item := range sortedValues(s.Checklists.items) {
+		records = append(records, fileRecord{ChecklistItem: &item})
+	}
+	for _, dependency := range s.Dependencies.dependencies {
+		records = append(records, fileRecord{Dependency: &dependency})
+	}
+	for _, entry := range s.Audit.entries {
+		records = append(records, fileRecord{AuditEntry: &entry})
+	}
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(s.path, data.Bytes()); err != nil {
+		return err
+	}
+
+	if s.log != nil {
+		s.log.Close()
+	}
+	log, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		s.log = nil
+		return err
+	}
+	s.log = log
+	s.records = len(records)
+	s.dirty = false
+	return nil
+}
+
+// writeJournal records a write in journal, if it is set and the write changes anything.
+func writeJournal(journal func(records []fileRecord) error, records ...fileRecord) error {
+	if journal == nil || len(records) == 0 {
+		return nil
+	}
+	return journal(records)
+}
+
+// sortedValues returns the values of a map ordered by their keys.
+func sortedValues[V any](values map[uint]V) []V {
+	keys := make([]uint, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	sorted := make([]V, len(keys))
+	for i, key := range keys {
+		sorted[i] = values[key]
+	}
+	return sorted
+}
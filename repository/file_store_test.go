@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := OpenFileStore(path)
+	assert.NoError(t, err)
+
+	kept, err := store.Tasks.CreateTask(t.Context(), model.Task{Name: "kept", Status: model.StatusNew})
+	assert.NoError(t, err)
+	_, err = store.Tasks.UpdateTaskById(t.Context(), kept.Id, model.Task{Name: "renamed", Status: model.StatusInProgress, Version: kept.Version})
+	assert.NoError(t, err)
+	deleted, err := store.Tasks.CreateTask(t.Context(), model.Task{Name: "deleted", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Tasks.DeleteByID(t.Context(), deleted.Id, 0))
+	purged, err := store.Tasks.CreateTask(t.Context(), model.Task{Name: "purged", Status: model.StatusNew, ParentId: &kept.Id})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Tasks.DeleteByID(t.Context(), purged.Id, 0))
+	assert.NoError(t, store.Tasks.Purge(t.Context(), purged.Id))
+	assert.NoError(t, store.Close())
+
+	store, err = OpenFileStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+	task, err := store.Tasks.FindById(t.Context(), kept.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", task.Name)
+	assert.Equal(t, model.StatusInProgress, task.Status)
+	assert.Equal(t, uint(2), task.Version)
+	assert.True(t, kept.CreatedAt.Equal(task.CreatedAt))
+	_, err = store.Tasks.FindDeletedById(t.Context(), deleted.Id)
+	assert.NoError(t, err)
+	_, err = store.Tasks.FindDeletedById(t.Context(), purged.Id)
+	assert.Error(t, err)
+
+	created, err := store.Tasks.CreateTask(t.Context(), model.Task{Name: "new", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Equal(t, purged.Id+1, created.Id)
+}
+
+func TestFileStore_Lock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := OpenFileStore(path)
+	assert.NoError(t, err)
+
+	_, err = OpenFileStore(path)
+	assert.ErrorIs(t, err, errFileLocked)
+
+	assert.NoError(t, store.Close())
+	store, err = OpenFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Close())
+}
+
+func TestFileStore_TornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := OpenFileStore(path)
+	assert.NoError(t, err)
+	_, err = store.Tasks.CreateTask(t.Context(), model.Task{Name: "first", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Close())
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"Task":{"Id":2,"Na`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	store, err = OpenFileStore(path)
+	assert.NoError(t, err)
+	tasks, err := store.Tasks.GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	second, err := store.Tasks.CreateTask(t.Context(), model.Task{Name: "second", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), second.Id)
+	assert.NoError(t, store.Close())
+
+	store, err = OpenFileStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+	tasks, err = store.Tasks.GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+}
+
+func TestFileStore_Compaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := OpenFileStore(path)
+	assert.NoError(t, err)
+	task, err := store.Tasks.CreateTask(t.Context(), model.Task{Name: "task", Status: model.StatusNew})
+	assert.NoError(t, err)
+	for i := 0; i < compactMinRecords+10; i++ {
+		task, err = store.Tasks.UpdateTaskById(t.Context(), task.Id, task)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, store.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Less(t, bytes.Count(data, []byte("\n")), 20)
+
+	store, err = OpenFileStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+	read, err := store.Tasks.FindById(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, task.Version, read.Version)
+}
+
+func TestFileStore_ReopenAttachments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := OpenFileStore(path)
+	assert.NoError(t, err)
+
+	ctx := t.Context()
+	task, err := store.Tasks.CreateTask(ctx, model.Task{Name: "task", Status: model.StatusNew})
+	assert.NoError(t, err)
+	blocker, err := store.Tasks.CreateTask(ctx, model.Task{Name: "blocker", Status: model.StatusNew})
+	assert.NoError(t, err)
+	backend, err := store.Tags.CreateTag(ctx, model.Tag{Name: "backend"})
+	assert.NoError(t, err)
+	removed, err := store.Tags.CreateTag(ctx, model.Tag{Name: "removed"})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Tags.AttachTags(ctx, task.Id, []uint{backend.Id, removed.Id}))
+	_, err = store.Tags.RenameTag(ctx, backend.Id, "api")
+	assert.NoError(t, err)
+	assert.NoError(t, store.Tags.DeleteTag(ctx, removed.Id))
+	_, err = store.Comments.CreateComment(ctx, model.Comment{TaskId: task.Id, Author: "alex", Body: "first"})
+	assert.NoError(t, err)
+	deleted, err := store.Comments.CreateComment(ctx, model.Comment{TaskId: task.Id, Author: "alex", Body: "deleted"})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Comments.DeleteComment(ctx, deleted.Id))
+	first, err := store.Checklists.AddItem(ctx, model.ChecklistItem{TaskId: task.Id, Text: "first"})
+	assert.NoError(t, err)
+	second, err := store.Checklists.AddItem(ctx, model.ChecklistItem{TaskId: task.Id, Text: "second"})
+	assert.NoError(t, err)
+	_, err = store.Checklists.Reorder(ctx, task.Id, []uint{second.Id, first.Id})
+	assert.NoError(t, err)
+	_, err = store.Dependencies.AddDependency(ctx, model.TaskDependency{TaskId: task.Id, BlockerId: blocker.Id})
+	assert.NoError(t, err)
+	_, err = store.Audit.Record(ctx, model.AuditEntry{TaskId: task.Id, Actor: "alex", Operation: model.AuditCreate})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Close())
+
+	for _, compacted := range []bool{false, true} {
+		store, err = OpenFileStore(path)
+		assert.NoError(t, err)
+
+		tags, err := store.Tags.FindByTask(ctx, task.Id)
+		assert.NoError(t, err)
+		assert.Len(t, tags, 1)
+		assert.Equal(t, "api", tags[0].Name)
+		_, err = store.Tags.FindById(ctx, removed.Id)
+		assert.Error(t, err)
+		comments, err := store.Comments.FindByTask(ctx, task.Id, 0, 0)
+		assert.NoError(t, err)
+		assert.Len(t, comments.Comments, 1)
+		items, err := store.Checklists.FindByTask(ctx, task.Id)
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, "second", items[0].Text)
+		blockers, err := store.Dependencies.FindBlockers(ctx, task.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{blocker.Id}, blockers)
+		entries, err := store.Audit.Find(ctx, model.AuditQuery{TaskId: task.Id})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), entries.Total)
+
+		if compacted {
+			comment, err := store.Comments.CreateComment(ctx, model.Comment{TaskId: task.Id, Author: "alex", Body: "new"})
+			assert.NoError(t, err)
+			assert.Equal(t, deleted.Id+1, comment.Id)
+		} else {
+			store.Tasks.mu.Lock()
+			assert.NoError(t, store.compact())
+			store.Tasks.mu.Unlock()
+		}
+		assert.NoError(t, store.Close())
+	}
+}
+
+func TestFileStore_InTransaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.jsonl")
+	store, err := OpenFileStore(path)
+	assert.NoError(t, err)
+	ctx := t.Context()
+	task, err := store.Tasks.CreateTask(ctx, model.Task{Name: "task", Status: model.StatusNew})
+	assert.NoError(t, err)
+	before, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	assert.Error(t, store.InTransaction(ctx, func(repos Repositories) error {
+		if err := repos.Tasks.DeleteByID(ctx, task.Id, 0); err != nil {
+			return err
+		}
+		return repos.Comments.DeleteComment(ctx, 42)
+	}))
+	after, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	assert.NoError(t, store.InTransaction(ctx, func(repos Repositories) error {
+		if err := repos.Tasks.DeleteByID(ctx, task.Id, 0); err != nil {
+			return err
+		}
+		_, err := repos.Audit.Record(ctx, model.AuditEntry{TaskId: task.Id, Operation: model.AuditDelete})
+		return err
+	}))
+	assert.NoError(t, store.Close())
+
+	store, err = OpenFileStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+	_, err = store.Tasks.FindDeletedById(ctx, task.Id)
+	assert.NoError(t, err)
+	page, err := store.Audit.Find(ctx, model.AuditQuery{TaskId: task.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"task_manager_go/model"
+)
+
+// MemoryAuditRepository implements AuditRepositoryInterface by keeping the audit trail in memory.
+// It is safe for concurrent use.
+type MemoryAuditRepository struct {
+	mu      rwLocker
+	entries []model.AuditEntry
+	// journal, if set, durably records the entries before they are stored
+	journal func(records []fileRecord) error
+}
+
+// NewMemoryAuditRepository creates a new, empty instance of MemoryAuditRepository.
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return newMemoryAuditRepository(new(sync.RWMutex))
+}
+
+// newMemoryAuditRepository creates an empty MemoryAuditRepository guarded by mu.
+func newMemoryAuditRepository(mu rwLocker) *MemoryAuditRepository {
+	return &MemoryAuditRepository{mu: mu}
+}
+
+// Record implements storing a new audit entry with the next id.
+func (r *MemoryAuditRepository) Record(ctx context.Context, entry model.AuditEntry) (model.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.Id = uint(len(r.entries) + 1)
+	if err := writeJournal(r.journal, fileRecord{AuditEntry: &entry}); err != nil {
+		return model.AuditEntry{}, err
+	}
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+// Find implements the retrieval of a page of the entries matching a query, most recent first.
+func (r *MemoryAuditRepository) Find(ctx context.Context, query model.AuditQuery) (model.AuditPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entries := make([]model.AuditEntry, 0)
+	for _, entry := range r.entries {
+		if (query.TaskId == 0 || entry.TaskId == query.TaskId) &&
+			(query.Actor == "" || entry.Actor == query.Actor) &&
+			(query.From == nil || !entry.At.Before(*query.From)) &&
+			(query.To == nil || entry.At.Before(*query.To)) {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b model.AuditEntry) int {
+		return cmp.Or(b.At.Compare(a.At), cmp.Compare(b.Id, a.Id))
+	})
+
+	page := model.AuditPage{Total: int64(len(entries)), Limit: query.Limit, Offset: query.Offset}
+	start := min(max(query.Offset, 0), len(entries))
+	end := len(entries)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, end)
+	}
+	page.Entries = entries[start:end]
+	return page, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"task_manager_go/model"
+	"time"
+)
+
+// MemoryChecklistRepository implements ChecklistRepositoryInterface by keeping checklist items in memory.
+// It is safe for concurrent use and never reuses ids.
+type MemoryChecklistRepository struct {
+	mu     rwLocker
+	items  map[uint]model.ChecklistItem
+	nextId uint
+	// journal, if set, durably records the stored and deleted items of a write before it is applied
+	journal func(records []fileRecord) error
+}
+
+// NewMemoryChecklistRepository creates a new, empty instance of MemoryChecklistRepository.
+func NewMemoryChecklistRepository() *MemoryChecklistRepository {
+	return newMemoryChecklistRepository(new(sync.RWMutex))
+}
+
+// newMemoryChecklistRepository creates an empty MemoryChecklistRepository guarded by mu.
+func newMemoryChecklistRepository(mu rwLocker) *MemoryChecklistRepository {
+	return &MemoryChecklistRepository{
+		mu:     mu,
+		items:  make(map[uint]model.ChecklistItem),
+		nextId: 1,
+	}
+}
+
+// AddItem implements storing a new item with the next id after the last item of its task.
+func (r *MemoryChecklistRepository) AddItem(ctx context.Context, item model.ChecklistItem) (model.ChecklistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item.Id = r.nextId
+	item.Position = 1
+	if items := r.findByTask(item.TaskId); len(items) > 0 {
+		item.Position = items[len(items)-1].Position + 1
+	}
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+	if err := r.commit(item); err != nil {
+		return model.ChecklistItem{}, err
+	}
+	r.nextId++
+	return item, nil
+}
+
+// FindByTask implements the retrieval of the items of a task in position order.
+func (r *MemoryChecklistRepository) FindByTask(ctx context.Context, taskId uint) ([]model.ChecklistItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.findByTask(taskId), nil
+}
+
+// FindById implements the retrieval of a checklist item by its ID.
+func (r *MemoryChecklistRepository) FindById(ctx context.Context, id uint) (model.ChecklistItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if item, exists := r.items[id]; exists {
+		return item, nil
+	}
+	return model.ChecklistItem{}, notFound("checklist item")
+}
+
+// ToggleItem implements flipping the done flag of an item.
+func (r *MemoryChecklistRepository) ToggleItem(ctx context.Context, id uint) (model.ChecklistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, exists := r.items[id]
+	if !exists {
+		return model.ChecklistItem{}, notFound("checklist item")
+	}
+	item.Done = !item.Done
+	item.UpdatedAt = time.Now()
+	if err := r.commit(item); err != nil {
+		return model.ChecklistItem{}, err
+	}
+	return item, nil
+}
+
+// Reorder implements assigning the positions of all items of a task in the order of itemIds.
+func (r *MemoryChecklistRepository) Reorder(ctx context.Context, taskId uint, itemIds []uint) ([]model.ChecklistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !sameItems(r.findByTask(taskId), itemIds) {
+		return nil, ErrChecklistMismatch
+	}
+	items := make([]model.ChecklistItem, len(itemIds))
+	for i, id := range itemIds {
+		items[i] = r.items[id]
+		items[i].Position = i + 1
+	}
+	if err := r.commit(items...); err != nil {
+		return nil, err
+	}
+	return r.findByTask(taskId), nil
+}
+
+// DeleteItem implements the removal of a checklist item by its ID.
+func (r *MemoryChecklistRepository) DeleteItem(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.items[id]; !exists {
+		return notFound("checklist item")
+	}
+	if err := writeJournal(r.journal, fileRecord{DeletedChecklistItem: id}); err != nil {
+		return err
+	}
+	delete(r.items, id)
+	return nil
+}
+
+// DeleteByTask implements the removal of every checklist item of a task.
+func (r *MemoryChecklistRepository) DeleteByTask(ctx context.Context, taskId uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var records []fileRecord
+	for id, item := range r.items {
+		if item.TaskId == taskId {
+			records = append(records, fileRecord{DeletedChecklistItem: id})
+		}
+	}
+	if err := writeJournal(r.journal, records...); err != nil {
+		return err
+	}
+	for _, record := range records {
+		delete(r.items, record.DeletedChecklistItem)
+	}
+	return nil
+}
+
+// commit records items in the journal, if any, and stores them. Nothing is stored if the journal fails.
+// The caller must hold the write lock.
+func (r *MemoryChecklistRepository) commit(items ...model.ChecklistItem) error {
+	records := make([]fileRecord, len(items))
+	for i := range items {
+		records[i] = fileRecord{ChecklistItem: &items[i]}
+	}
+	if err := writeJournal(r.journal, records...); err != nil {
+		return err
+	}
+	for _, item := range items {
+		r.items[item.Id] = item
+	}
+	return nil
+}
+
+// findByTask returns the items of a task in position order. The caller must hold a lock.
+func (r *MemoryChecklistRepository) findByTask(taskId uint) []model.ChecklistItem {
+	items := make([]model.ChecklistItem, 0)
+	for _, item := range r.items {
+		if item.TaskId == taskId {
+			items = append(items, item)
+		}
+	}
+	slices.SortFunc(items, func(a, b model.ChecklistItem) int {
+		if order := cmp.Compare(a.Position, b.Position); order != 0 {
+			return order
+		}
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return items
+}
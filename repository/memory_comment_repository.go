@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"task_manager_go/model"
+	"time"
+)
+
+// MemoryCommentRepository implements CommentRepositoryInterface by keeping comments in memory.
+// It is safe for concurrent use and never reuses ids.
+type MemoryCommentRepository struct {
+	mu       rwLocker
+	comments map[uint]model.Comment
+	nextId   uint
+	// journal, if set, durably records the stored and deleted comments of a write before it is applied
+	journal func(records []fileRecord) error
+}
+
+// NewMemoryCommentRepository creates a new, empty instance of MemoryCommentRepository.
+func NewMemoryCommentRepository() *MemoryCommentRepository {
+	return newMemoryCommentRepository(new(sync.RWMutex))
+}
+
+// newMemoryCommentRepository creates an empty MemoryCommentRepository guarded by mu.
+func newMemoryCommentRepository(mu rwLocker) *MemoryCommentRepository {
+	return &MemoryCommentRepository{
+		mu:       mu,
+		comments: make(map[uint]model.Comment),
+		nextId:   1,
+	}
+}
+
+// CreateComment implements storing a new comment with the next id.
+func (r *MemoryCommentRepository) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment.Id = r.nextId
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+	if err := writeJournal(r.journal, fileRecord{Comment: &comment}); err != nil {
+		return model.Comment{}, err
+	}
+	r.nextId++
+	r.comments[comment.Id] = comment
+	return comment, nil
+}
+
+// FindByTask implements the retrieval of a page of the comments of a task, oldest first.
+func (r *MemoryCommentRepository) FindByTask(ctx context.Context, taskId uint, limit, offset int) (model.CommentPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	comments := make([]model.Comment, 0)
+	for _, comment := range r.comments {
+		if comment.TaskId == taskId {
+			comments = append(comments, comment)
+		}
+	}
+	slices.SortFunc(comments, func(a, b model.Comment) int {
+		if order := a.CreatedAt.Compare(b.CreatedAt); order != 0 {
+			return order
+		}
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	page := model.CommentPage{Total: int64(len(comments)), Limit: limit, Offset: offset}
+	start := min(max(offset, 0), len(comments))
+	end := len(comments)
+	if limit > 0 {
+		end = min(start+limit, end)
+	}
+	page.Comments = comments[start:end]
+	return page, nil
+}
+
+// FindById implements the retrieval of a comment by its ID.
+func (r *MemoryCommentRepository) FindById(ctx context.Context, id uint) (model.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if comment, exists := r.comments[id]; exists {
+		return comment, nil
+	}
+	return model.Comment{}, notFound("comment")
+}
+
+// UpdateComment implements replacing the body of a comment and marking it as edited.
+func (r *MemoryCommentRepository) UpdateComment(ctx context.Context, id uint, body string) (model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment, exists := r.comments[id]
+	if !exists {
+		return model.Comment{}, notFound("comment")
+	}
+	comment.Body = body
+	comment.Edited = true
+	comment.UpdatedAt = time.Now()
+	if err := writeJournal(r.journal, fileRecord{Comment: &comment}); err != nil {
+		return model.Comment{}, err
+	}
+	r.comments[id] = comment
+	return comment, nil
+}
+
+// DeleteComment implements the removal of a comment by its ID.
+func (r *MemoryCommentRepository) DeleteComment(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.comments[id]; !exists {
+		return notFound("comment")
+	}
+	if err := writeJournal(r.journal, fileRecord{DeletedComment: id}); err != nil {
+		return err
+	}
+	delete(r.comments, id)
+	return nil
+}
+
+// DeleteByTask implements the removal of every comment of a task.
+func (r *MemoryCommentRepository) DeleteByTask(ctx context.Context, taskId uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var records []fileRecord
+	for id, comment := range r.comments {
+		if comment.TaskId == taskId {
+			records = append(records, fileRecord{DeletedComment: id})
+		}
+	}
+	if err := writeJournal(r.journal, records...); err != nil {
+		return err
+	}
+	for _, record := range records {
+		delete(r.comments, record.DeletedComment)
+	}
+	return nil
+}
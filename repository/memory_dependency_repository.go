@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"task_manager_go/model"
+	"time"
+)
+
+// dependencyKey identifies a dependency in a MemoryDependencyRepository.
+type dependencyKey struct {
+	taskId    uint
+	blockerId uint
+}
+
+// MemoryDependencyRepository implements DependencyRepositoryInterface by keeping dependencies in memory.
+// It is safe for concurrent use.
+type MemoryDependencyRepository struct {
+	mu           rwLocker
+	dependencies map[dependencyKey]model.TaskDependency
+	// journal, if set, durably records the added and removed dependencies of a write before it is applied
+	journal func(records []fileRecord) error
+}
+
+// NewMemoryDependencyRepository creates a new, empty instance of MemoryDependencyRepository.
+func NewMemoryDependencyRepository() *MemoryDependencyRepository {
+	return newMemoryDependencyRepository(new(sync.RWMutex))
+}
+
+// newMemoryDependencyRepository creates an empty MemoryDependencyRepository guarded by mu.
+func newMemoryDependencyRepository(mu rwLocker) *MemoryDependencyRepository {
+	return &MemoryDependencyRepository{
+		mu:           mu,
+		dependencies: make(map[dependencyKey]model.TaskDependency),
+	}
+}
+
+// AddDependency implements storing a dependency, returning the stored one if it already exists.
+func (r *MemoryDependencyRepository) AddDependency(ctx context.Context, dependency model.TaskDependency) (model.TaskDependency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dependencyKey{dependency.TaskId, dependency.BlockerId}
+	if existing, exists := r.dependencies[key]; exists {
+		return existing, nil
+	}
+	dependency.CreatedAt = time.Now()
+	if err := writeJournal(r.journal, fileRecord{Dependency: &dependency}); err != nil {
+		return model.TaskDependency{}, err
+	}
+	r.dependencies[key] = dependency
+	return dependency, nil
+}
+
+// RemoveDependency implements the removal of the dependency of a task on a blocker.
+func (r *MemoryDependencyRepository) RemoveDependency(ctx context.Context, taskId, blockerId uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := dependencyKey{taskId, blockerId}
+	dependency, exists := r.dependencies[key]
+	if !exists {
+		return notFound("dependency")
+	}
+	if err := writeJournal(r.journal, fileRecord{RemovedDependency: &dependency}); err != nil {
+		return err
+	}
+	delete(r.dependencies, key)
+	return nil
+}
+
+// FindBlockers implements the retrieval of the ids of the direct blockers of a task in ascending order.
+func (r *MemoryDependencyRepository) FindBlockers(ctx context.Context, taskId uint) ([]uint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]uint, 0)
+	for key := range r.dependencies {
+		if key.taskId == taskId {
+			ids = append(ids, key.blockerId)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// DeleteByTask implements the removal of every dependency a task takes part in.
+func (r *MemoryDependencyRepository) DeleteByTask(ctx context.Context, taskId uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var records []fileRecord
+	for key, dependency := range r.dependencies {
+		if key.taskId == taskId || key.blockerId == taskId {
+			records = append(records, fileRecord{RemovedDependency: &dependency})
+		}
+	}
+	if err := writeJournal(r.journal, records...); err != nil {
+		return err
+	}
+	for _, record := range records {
+		delete(r.dependencies, dependencyKey{record.RemovedDependency.TaskId, record.RemovedDependency.BlockerId})
+	}
+	return nil
+}
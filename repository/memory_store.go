@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"slices"
+)
+
+// rwLocker is the lock of a memory repository: the *sync.RWMutex its store shares between its repositories,
+// or heldLock for the repositories of a transaction, which holds that lock already.
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// heldLock is the rwLocker of the repositories of a memoryTransaction; locking it does nothing.
+type heldLock struct{}
+
+func (heldLock) Lock()    {}
+func (heldLock) Unlock()  {}
+func (heldLock) RLock()   {}
+func (heldLock) RUnlock() {}
+
+// MemoryStore bundles memory repositories that share the lock of their task repository,
+// so that it can run changes of several of them as one transaction. It implements Transactor.
+type MemoryStore struct {
+	Tasks        *MemoryTaskRepository
+	Tags         *MemoryTagRepository
+	Audit        *MemoryAuditRepository
+	Dependencies *MemoryDependencyRepository
+	Comments     *MemoryCommentRepository
+	Checklists   *MemoryChecklistRepository
+
+	// prepare, if set, readies the journal before a transaction changes anything
+	prepare func() error
+	// journal, if set, durably records the records of a transaction at once before it is committed
+	journal func(records []fileRecord) error
+}
+
+// NewMemoryStore creates a new instance of MemoryStore for the tasks of tasks,
+// with empty repositories for everything else.
+func NewMemoryStore(tasks *MemoryTaskRepository) *MemoryStore {
+	return &MemoryStore{
+		Tasks:        tasks,
+		Tags:         NewMemoryTagRepository(tasks),
+		Audit:        newMemoryAuditRepository(tasks.mu),
+		Dependencies: newMemoryDependencyRepository(tasks.mu),
+		Comments:     newMemoryCommentRepository(tasks.mu),
+		Checklists:   newMemoryChecklistRepository(tasks.mu),
+	}
+}
+
+// Repositories returns the repositories of the store.
+func (s *MemoryStore) Repositories() Repositories {
+	return Repositories{
+		Tasks:        s.Tasks,
+		Tags:         s.Tags,
+		Audit:        s.Audit,
+		Dependencies: s.Dependencies,
+		Comments:     s.Comments,
+		Checklists:   s.Checklists,
+	}
+}
+
+// InTransaction implements running fn with repositories whose changes are kept only if fn returns nil.
+// The store stays locked until fn returns, so other requests neither see nor interleave with its changes;
+// if fn fails or its changes cannot be journaled, every change it made is undone.
+// fn must use only the repositories it is given.
+func (s *MemoryStore) InTransaction(ctx context.Context, fn func(repos Repositories) error) error {
+	s.Tasks.mu.Lock()
+	defer s.Tasks.mu.Unlock()
+	if s.prepare != nil {
+		if err := s.prepare(); err != nil {
+			return err
+		}
+	}
+	tx := s.begin()
+	if err := fn(tx.repositories()); err != nil {
+		tx.rollback()
+		return err
+	}
+	if err := writeJournal(s.journal, tx.records...); err != nil {
+		tx.rollback()
+		return err
+	}
+	tx.commit()
+	return nil
+}
+
+// memoryTransaction is a transaction of a MemoryStore.
+// Its repositories are copies of the repositories of the store that share their maps, skip the lock
+// the transaction holds and journal into the transaction, which remembers how to undo every change.
+// Ids and the audit trail are advanced in the copies; on commit they are handed back to the store
+// and the records go to the journal of the store.
+type memoryTransaction struct {
+	store        *MemoryStore
+	tasks        *MemoryTaskRepository
+	tags         *MemoryTagRepository
+	audit        *MemoryAuditRepository
+	dependencies *MemoryDependencyRepository
+	comments     *MemoryCommentRepository
+	checklists   *MemoryChecklistRepository
+	// records are the journal records of the changes, in the order they were made
+	records []fileRecord
+	// undo restores the values the changes replaced, one function per record
+	undo []func()
+}
+
+// begin starts a transaction of the store. The caller must hold the write lock until it ends.
+func (s *MemoryStore) begin() *memoryTransaction {
+	tx := &memoryTransaction{store: s}
+	tasks, tags, audit := *s.Tasks, *s.Tags, *s.Audit
+	dependencies, comments, checklists := *s.Dependencies, *s.Comments, *s.Checklists
+	tasks.mu, tasks.journal = heldLock{}, tx.journal
+	tags.tasks = &tasks
+	audit.mu, audit.journal = heldLock{}, tx.journal
+	dependencies.mu, dependencies.journal = heldLock{}, tx.journal
+	comments.mu, comments.journal = heldLock{}, tx.journal
+	checklists.mu, checklists.journal = heldLock{}, tx.journal
+	tx.tasks, tx.tags, tx.audit = &tasks, &tags, &audit
+	tx.dependencies, tx.comments, tx.checklists = &dependencies, &comments, &checklists
+	return tx
+}
+
+// repositories returns the repositories of the transaction.
+func (tx *memoryTransaction) repositories() Repositories {
+	return Repositories{
+		Tasks:        tx.tasks,
+		Tags:         tx.tags,
+		Audit:        tx.audit,
+		Dependencies: tx.dependencies,
+		Comments:     tx.comments,
+		Checklists:   tx.checklists,
+	}
+}
+
+// journal collects the records of a change and remembers the values they are about to replace.
+func (tx *memoryTransaction) journal(records []fileRecord) error {
+	for _, record := range records {
+		tx.undo = append(tx.undo, tx.undoRecord(record))
+	}
+	tx.records = append(tx.records, records...)
+	return nil
+}
+
+// undoRecord returns a function restoring the value record is about to replace.
+// Audit entries need no undo, they are only appended to the copy of the transaction.
+func (tx *memoryTransaction) undoRecord(record fileRecord) func() {
+	switch {
+	case record.Task != nil:
+		return restoreValue(tx.tasks.tasks, record.Task.Id)
+	case record.Purged != 0:
+		return restoreValue(tx.tasks.tasks, record.Purged)
+	case record.Tag != nil:
+		return restoreValue(tx.tags.tags, record.Tag.Id)
+	case record.DeletedTag != 0:
+		return restoreValue(tx.tags.tags, record.DeletedTag)
+	case record.Comment != nil:
+		return restoreValue(tx.comments.comments, record.Comment.Id)
+	case record.DeletedComment != 0:
+		return restoreValue(tx.comments.comments, record.DeletedComment)
+	case record.ChecklistItem != nil:
+		return restoreValue(tx.checklists.items, record.ChecklistItem.Id)
+	case record.DeletedChecklistItem != 0:
+		return restoreValue(tx.checklists.items, record.DeletedChecklistItem)
+	case record.Dependency != nil:
+		return restoreValue(tx.dependencies.dependencies, dependencyKey{record.Dependency.TaskId, record.Dependency.BlockerId})
+	case record.RemovedDependency != nil:
+		return restoreValue(tx.dependencies.dependencies, dependencyKey{record.RemovedDependency.TaskId, record.RemovedDependency.BlockerId})
+	}
+	return nil
+}
+
+// commit hands the ids, the audit trail and the change count the transaction advanced back to the store.
+func (tx *memoryTransaction) commit() {
+	s := tx.store
+	s.Tasks.nextId, s.Tasks.changes = tx.tasks.nextId, tx.tasks.changes
+	s.Tags.nextId = tx.tags.nextId
+	s.Audit.entries = tx.audit.entries
+	s.Comments.nextId = tx.comments.nextId
+	s.Checklists.nextId = tx.checklists.nextId
+}
+
+// rollback undoes the changes of the transaction, latest first.
+func (tx *memoryTransaction) rollback() {
+	for _, undo := range slices.Backward(tx.undo) {
+		if undo != nil {
+			undo()
+		}
+	}
+}
+
+// restoreValue returns a function that puts back the value stored under key now, or removes the key if there is none.
+func restoreValue[K comparable, V any](values map[K]V, key K) func() {
+	value, exists := values[key]
+	return func() {
+		if exists {
+			values[key] = value
+		} else {
+			delete(values, key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_InTransaction(t *testing.T) {
+	store := NewMemoryStore(NewMemoryTaskRepository())
+	ctx := t.Context()
+	parent, err := store.Tasks.CreateTask(ctx, model.Task{Name: "parent", Status: model.StatusNew})
+	assert.NoError(t, err)
+	child, err := store.Tasks.CreateTask(ctx, model.Task{Name: "child", Status: model.StatusNew, ParentId: &parent.Id})
+	assert.NoError(t, err)
+
+	failed := errors.New("failed")
+	err = store.InTransaction(ctx, func(repos Repositories) error {
+		if err := repos.Tasks.DeleteByID(ctx, child.Id, 0); err != nil {
+			return err
+		}
+		if _, err := repos.Audit.Record(ctx, model.AuditEntry{TaskId: child.Id, Operation: model.AuditDelete}); err != nil {
+			return err
+		}
+		if _, err := repos.Tasks.CreateTask(ctx, model.Task{Name: "other", Status: model.StatusNew}); err != nil {
+			return err
+		}
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	_, err = store.Tasks.FindById(ctx, child.Id)
+	assert.NoError(t, err)
+	all, err := store.Tasks.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	page, err := store.Audit.Find(ctx, model.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Zero(t, page.Total)
+
+	err = store.InTransaction(ctx, func(repos Repositories) error {
+		if err := repos.Tasks.DeleteByID(ctx, child.Id, 0); err != nil {
+			return err
+		}
+		_, err := repos.Audit.Record(ctx, model.AuditEntry{TaskId: child.Id, Operation: model.AuditDelete})
+		return err
+	})
+	assert.NoError(t, err)
+	_, err = store.Tasks.FindDeletedById(ctx, child.Id)
+	assert.NoError(t, err)
+	page, err = store.Audit.Find(ctx, model.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	created, err := store.Tasks.CreateTask(ctx, model.Task{Name: "next", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Equal(t, child.Id+1, created.Id)
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"task_manager_go/model"
+	"time"
+)
+
+// MemoryTagRepository implements TagRepositoryInterface by keeping tags in memory
+// and attaching them to the tasks of a MemoryTaskRepository.
+// It shares the lock and the journal of the task repository, so that a change of a tag
+// and of the tasks carrying it is recorded and applied at once.
+type MemoryTagRepository struct {
+	tasks  *MemoryTaskRepository
+	tags   map[uint]model.Tag
+	nextId uint
+}
+
+// NewMemoryTagRepository creates a new instance of MemoryTagRepository without tags for the tasks of tasks.
+func NewMemoryTagRepository(tasks *MemoryTaskRepository) *MemoryTagRepository {
+	return &MemoryTagRepository{
+		tasks:  tasks,
+		tags:   make(map[uint]model.Tag),
+		nextId: 1,
+	}
+}
+
+// CreateTag implements storing a new tag with the next id.
+// Returns a conflict error if a tag with the same name exists.
+func (r *MemoryTagRepository) CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	r.tasks.mu.Lock()
+	defer r.tasks.mu.Unlock()
+	if r.findByName(tag.Name) != nil {
+		return model.Tag{}, model.NewError(model.ErrConflict, "tag already exists")
+	}
+	tag.Id = r.nextId
+	tag.CreatedAt = time.Now()
+	if err := writeJournal(r.tasks.journal, fileRecord{Tag: &tag}); err != nil {
+		return model.Tag{}, err
+	}
+	r.nextId++
+	r.tags[tag.Id] = tag
+	return tag, nil
+}
+
+// GetAll implements the retrieval of all tags ordered by name.
+func (r *MemoryTagRepository) GetAll(ctx context.Context) ([]model.Tag, error) {
+	r.tasks.mu.RLock()
+	defer r.tasks.mu.RUnlock()
+	tags := make([]model.Tag, 0, len(r.tags))
+	for _, tag := range r.tags {
+		tags = append(tags, tag)
+	}
+	sortTags(tags)
+	return tags, nil
+}
+
+// FindById implements the retrieval of a tag by its ID.
+func (r *MemoryTagRepository) FindById(ctx context.Context, id uint) (model.Tag, error) {
+	r.tasks.mu.RLock()
+	defer r.tasks.mu.RUnlock()
+	if tag, exists := r.tags[id]; exists {
+		return tag, nil
+	}
+	return model.Tag{}, notFound("tag")
+}
+
+// FindByName implements the retrieval of a tag by its name.
+func (r *MemoryTagRepository) FindByName(ctx context.Context, name string) (model.Tag, error) {
+	r.tasks.mu.RLock()
+	defer r.tasks.mu.RUnlock()
+	if tag := r.findByName(name); tag != nil {
+		return *tag, nil
+	}
+	return model.Tag{}, notFound("tag")
+}
+
+// RenameTag implements renaming a tag on its own and on every task carrying it.
+func (r *MemoryTagRepository) RenameTag(ctx context.Context, id uint, name string) (model.Tag, error) {
+	r.tasks.mu.Lock()
+	defer r.tasks.mu.Unlock()
+	tag, exists := r.tags[id]
+	if !exists {
+		return model.Tag{}, notFound("tag")
+	}
+	tag.Name = name
+	err := r.commit(func(tags []model.Tag) []model.Tag {
+		for i := range tags {
+			if tags[i].Id == id {
+				tags[i] = tag
+			}
+		}
+		return tags
+	}, fileRecord{Tag: &tag})
+	if err != nil {
+		return model.Tag{}, err
+	}
+	r.tags[id] = tag
+	return tag, nil
+}
+
+// MergeTags implements replacing the source tag with the target tag on every task and removing the source tag.
+func (r *MemoryTagRepository) MergeTags(ctx context.Context, sourceId, targetId uint) error {
+	r.tasks.mu.Lock()
+	defer r.tasks.mu.Unlock()
+	target, exists := r.tags[targetId]
+	if _, sourceExists := r.tags[sourceId]; !exists || !sourceExists {
+		return notFound("tag")
+	}
+	err := r.commit(func(tags []model.Tag) []model.Tag {
+		if !containsTag(tags, sourceId) {
+			return tags
+		}
+		tags = removeTag(tags, sourceId)
+		if !containsTag(tags, targetId) {
+			tags = append(tags, target)
+		}
+		return tags
+	}, fileRecord{DeletedTag: sourceId})
+	if err != nil {
+		return err
+	}
+	delete(r.tags, sourceId)
+	return nil
+}
+
+// DeleteTag implements the removal of a tag from the tags and from every task carrying it.
+func (r *MemoryTagRepository) DeleteTag(ctx context.Context, id uint) error {
+	r.tasks.mu.Lock()
+	defer r.tasks.mu.Unlock()
+	if _, exists := r.tags[id]; !exists {
+		return notFound("tag")
+	}
+	err := r.commit(func(tags []model.Tag) []model.Tag {
+		return removeTag(tags, id)
+	}, fileRecord{DeletedTag: id})
+	if err != nil {
+		return err
+	}
+	delete(r.tags, id)
+	return nil
+}
+
+// FindByTask implements the retrieval of the tags of a task, in the trash or not, ordered by name.
+func (r *MemoryTagRepository) FindByTask(ctx context.Context, taskId uint) ([]model.Tag, error) {
+	r.tasks.mu.RLock()
+	defer r.tasks.mu.RUnlock()
+	task, exists := r.tasks.tasks[taskId]
+	if !exists {
+		return nil, notFound("task")
+	}
+	return slices.Clone(task.Tags), nil
+}
+
+// AttachTags implements adding tags to a task, tags the task already carries are skipped.
+func (r *MemoryTagRepository) AttachTags(ctx context.Context, taskId uint, tagIds []uint) error {
+	r.tasks.mu.Lock()
+	defer r.tasks.mu.Unlock()
+	task, exists := r.tasks.tasks[taskId]
+	if !exists {
+		return notFound("task")
+	}
+	tags := slices.Clone(task.Tags)
+	for _, id := range tagIds {
+		tag, exists := r.tags[id]
+		if !exists {
+			return notFound("tag")
+		}
+		if !containsTag(tags, id) {
+			tags = append(tags, tag)
+		}
+	}
+	sortTags(tags)
+	task.Tags = tags
+	return r.tasks.commit(0, task)
+}
+
+// DetachTag implements removing a tag from a task.
+func (r *MemoryTagRepository) DetachTag(ctx context.Context, taskId, tagId uint) error {
+	r.tasks.mu.Lock()
+	defer r.tasks.mu.Unlock()
+	task, exists := r.tasks.tasks[taskId]
+	if !exists {
+		return notFound("task")
+	}
+	task.Tags = removeTag(task.Tags, tagId)
+	return r.tasks.commit(0, task)
+}
+
+// commit applies change to the tags of every task and records the changed tasks together with records
+// in the journal, if any, then stores them. Nothing is stored if the journal fails.
+// The caller must hold the write lock and apply records to the tags afterwards.
+func (r *MemoryTagRepository) commit(change func([]model.Tag) []model.Tag, records ...fileRecord) error {
+	var changed []model.Task
+	for _, task := range r.tasks.tasks {
+		tags := change(slices.Clone(task.Tags))
+		sortTags(tags)
+		if !slices.EqualFunc(tags, task.Tags, sameTag) {
+			task.Tags = tags
+			changed = append(changed, task)
+		}
+	}
+	for i := range changed {
+		records = append(records, fileRecord{Task: &changed[i]})
+	}
+	if err := writeJournal(r.tasks.journal, records...); err != nil {
+		return err
+	}
+	for _, task := range changed {
+		r.tasks.store(task)
+	}
+	return nil
+}
+
+// findByName returns the tag with the given name, or nil if there is none. The caller must hold a lock.
+func (r *MemoryTagRepository) findByName(name string) *model.Tag {
+	for _, tag := range r.tags {
+		if tag.Name == name {
+			return &tag
+		}
+	}
+	return nil
+}
+
+// sameTag reports whether a and b are the same tag under the same name.
+func sameTag(a, b model.Tag) bool {
+	return a.Id == b.Id && a.Name == b.Name
+}
+
+// containsTag reports whether tags contains the tag with the given id.
+func containsTag(tags []model.Tag, id uint) bool {
+	return slices.ContainsFunc(tags, func(tag model.Tag) bool { return tag.Id == id })
+}
+
+// removeTag returns a copy of tags without the tag with the given id.
+func removeTag(tags []model.Tag, id uint) []model.Tag {
+	return slices.DeleteFunc(append([]model.Tag{}, tags...), func(tag model.Tag) bool { return tag.Id == id })
+}
+
+// sortTags orders tags by name.
+func sortTags(tags []model.Tag) {
+	slices.SortFunc(tags, func(a, b model.Tag) int { return cmp.Compare(a.Name, b.Name) })
+}
@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"task_manager_go/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MemoryTaskRepository implements TaskRepositoryInterface by keeping tasks in memory.
+// It is safe for concurrent use. Ids are never reused, lists are ordered like in TaskRepository,
+// and tasks are copied on every write and read, so callers cannot change stored tasks by accident.
+// The tasks can be saved to a snapshot file and loaded from it when the server starts.
+type MemoryTaskRepository struct {
+	mu     rwLocker
+	tasks  map[uint]model.Task
+	nextId uint
+	// changes counts the writes, saved is the count the last snapshot was taken at
+	changes, saved uint64
+	// journal, if set, durably records the stored and purged tasks of a write before it is applied
+	journal func(records []fileRecord) error
+}
+
+// memorySnapshot is the content of a snapshot file of a MemoryTaskRepository.
+type memorySnapshot struct {
+	NextId uint
+	Tasks  []model.Task
+}
+
+// NewMemoryTaskRepository creates a new, empty instance of MemoryTaskRepository.
+func NewMemoryTaskRepository() *MemoryTaskRepository {
+	return &MemoryTaskRepository{
+		mu:     new(sync.RWMutex),
+		tasks:  make(map[uint]model.Task),
+		nextId: 1,
+	}
+}
+
+// LoadMemoryTaskRepository creates a new instance of MemoryTaskRepository with the tasks saved at path by SaveSnapshot.
+// Returns an empty repository if the file does not exist yet.
+func LoadMemoryTaskRepository(path string) (*MemoryTaskRepository, error) {
+	r := NewMemoryTaskRepository()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot memorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	for _, task := range snapshot.Tasks {
+		r.tasks[task.Id] = task
+		r.nextId = max(r.nextId, task.Id+1)
+	}
+	r.nextId = max(r.nextId, snapshot.NextId)
+	return r, nil
+}
+
+// CreateTask implements the creation of a new task with the next id.
+// Fills in the defaults the database would fill in, and leaves out tags like TaskRepository does.
+func (r *MemoryTaskRepository) CreateTask(ctx context.Context, task model.Task) (model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	task = copyTask(task)
+	task.Id = r.nextId
+	task.Version = 1
+	if task.Date.IsZero() {
+		task.Date = now
+	}
+	if task.Priority == 0 {
+		task.Priority = model.PriorityMedium
+	}
+	if task.Occurrence == 0 {
+		task.Occurrence = 1
+	}
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	task.DeletedAt = gorm.DeletedAt{}
+	task.Tags = nil
+	if err := r.commit(0, task); err != nil {
+		return model.Task{}, err
+	}
+	r.nextId++
+	return copyTask(task), nil
+}
+
+// GetAll implements the retrieval of all tasks ordered by id.
+func (r *MemoryTaskRepository) GetAll(ctx context.Context) ([]model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.filter(func(task model.Task) bool { return !task.DeletedAt.Valid }), nil
+}
+
+// Find implements the retrieval of a filtered, sorted and paginated list of tasks.
+func (r *MemoryTaskRepository) Find(ctx context.Context, query model.TaskQuery) (model.TaskPage, error) {
+	tasks, _ := r.GetAll(ctx)
+	return queryTasks(tasks, query), nil
+}
+
+// CountByStatus implements counting the tasks by status.
+func (r *MemoryTaskRepository) CountByStatus(ctx context.Context) (map[model.Status]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	counts := make(map[model.Status]int64)
+	for _, task := range r.tasks {
+		if !task.DeletedAt.Valid {
+			counts[task.Status]++
+		}
+	}
+	return counts, nil
+}
+
+// FindById implements the retrieval of a task by its ID.
+func (r *MemoryTaskRepository) FindById(ctx context.Context, id uint) (model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if task, exists := r.tasks[id]; exists && !task.DeletedAt.Valid {
+		return copyTask(task), nil
+	}
+	return model.Task{}, notFound("task")
+}
+
+// FindChildren implements the retrieval of the direct subtasks of a task ordered by id.
+func (r *MemoryTaskRepository) FindChildren(ctx context.Context, parentId uint) ([]model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.filter(func(task model.Task) bool {
+		return task.ParentId != nil && *task.ParentId == parentId && !task.DeletedAt.Valid
+	}), nil
+}
+
+// UpdateTaskById implements the update of an existing task.
+// The version is checked and incremented under the same lock, so concurrent updates cannot both succeed.
+// Tags and creation timestamps are left untouched like in TaskRepository.
+func (r *MemoryTaskRepository) UpdateTaskById(ctx context.Context, id uint, task model.Task) (model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, exists := r.tasks[id]
+	if !exists || existing.DeletedAt.Valid {
+		return model.Task{}, notFound("task")
+	}
+	if task.Version != 0 && task.Version != existing.Version {
+		return model.Task{}, errVersionMismatch
+	}
+	task = copyTask(task)
+	task.Id = id
+	task.Version = existing.Version + 1
+	task.Date = existing.Date
+	task.CreatedAt = existing.CreatedAt
+	task.UpdatedAt = time.Now()
+	task.DeletedAt = existing.DeletedAt
+	task.Tags = existing.Tags
+	if err := r.commit(0, task); err != nil {
+		return model.Task{}, err
+	}
+	return copyTask(task), nil
+}
+
+// DeleteByID implements moving a task to the trash by setting its DeletedAt.
+// The version is checked under the same lock as in UpdateTaskById.
+func (r *MemoryTaskRepository) DeleteByID(ctx context.Context, id uint, version uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, exists := r.tasks[id]
+	if !exists || task.DeletedAt.Valid {
+		return notFound("task")
+	}
+	if version != 0 && version != task.Version {
+		return errVersionMismatch
+	}
+	task.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return r.commit(0, task)
+}
+
+// FindDeleted implements the retrieval of the tasks in the trash, most recently deleted first.
+func (r *MemoryTaskRepository) FindDeleted(ctx context.Context) ([]model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tasks := r.filter(func(task model.Task) bool { return task.DeletedAt.Valid })
+	slices.SortStableFunc(tasks, func(a, b model.Task) int {
+		return b.DeletedAt.Time.Compare(a.DeletedAt.Time)
+	})
+	return tasks, nil
+}
+
+// FindDeletedById implements the retrieval of a task in the trash by its ID.
+func (r *MemoryTaskRepository) FindDeletedById(ctx context.Context, id uint) (model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if task, exists := r.tasks[id]; exists && task.DeletedAt.Valid {
+		return copyTask(task), nil
+	}
+	return model.Task{}, notFound("deleted task")
+}
+
+// Restore implements taking a task out of the trash by clearing its DeletedAt.
+func (r *MemoryTaskRepository) Restore(ctx context.Context, id uint) (model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, exists := r.tasks[id]
+	if !exists || !task.DeletedAt.Valid {
+		return model.Task{}, notFound("deleted task")
+	}
+	task.DeletedAt = gorm.DeletedAt{}
+	task.Version++
+	task.UpdatedAt = time.Now()
+	if err := r.commit(0, task); err != nil {
+		return model.Task{}, err
+	}
+	return copyTask(task), nil
+}
+
+// Touch implements incrementing the version of a task, in the trash or not.
+func (r *MemoryTaskRepository) Touch(ctx context.Context, id uint) (model.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, exists := r.tasks[id]
+	if !exists {
+		return model.Task{}, notFound("task")
+	}
+	task.Version++
+	task.UpdatedAt = time.Now()
+	if err := r.commit(0, task); err != nil {
+		return model.Task{}, err
+	}
+	return copyTask(task), nil
+}
+
+// Purge implements the permanent removal of a task in the trash.
+// Its id is not given to another task.
+func (r *MemoryTaskRepository) Purge(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if task, exists := r.tasks[id]; !exists || !task.DeletedAt.Valid {
+		return notFound("deleted task")
+	}
+	var detached []model.Task
+	now := time.Now()
+	for _, task := range r.tasks {
+		if task.Id == id {
+			continue
+		}
+		parent := task.ParentId != nil && *task.ParentId == id
+		next := task.NextOccurrenceId != nil && *task.NextOccurrenceId == id
+		if parent {
+			task.ParentId = nil
+		}
+		if next {
+			task.NextOccurrenceId = nil
+		}
+		if parent || next {
+			task.UpdatedAt = now
+			detached = append(detached, task)
+		}
+	}
+	return r.commit(id, detached...)
+}
+
+// SaveSnapshot writes every task, including the ones in the trash, to the file at path.
+// The file is replaced atomically, so a crash leaves either the previous or the new snapshot behind.
+// Tags are not part of the snapshot, they belong to the tag repository.
+func (r *MemoryTaskRepository) SaveSnapshot(path string) error {
+	r.mu.RLock()
+	snapshot := memorySnapshot{NextId: r.nextId, Tasks: r.filter(func(model.Task) bool { return true })}
+	changes := r.changes
+	r.mu.RUnlock()
+
+	for i := range snapshot.Tasks {
+		snapshot.Tasks[i].Tags = nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.saved = max(r.saved, changes)
+	r.mu.Unlock()
+	return nil
+}
+
+// RunSnapshots saves a snapshot to path every interval in which tasks were changed, until ctx is done,
+// and saves a last one when it is. Failed snapshots are logged and retried at the next interval.
+func (r *MemoryTaskRepository) RunSnapshots(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.saveIfChanged(path)
+			return
+		case <-ticker.C:
+			r.saveIfChanged(path)
+		}
+	}
+}
+
+// saveIfChanged saves a snapshot to path if tasks were changed since the last one.
+func (r *MemoryTaskRepository) saveIfChanged(path string) {
+	r.mu.RLock()
+	changed := r.changes != r.saved
+	r.mu.RUnlock()
+	if !changed {
+		return
+	}
+	if err := r.SaveSnapshot(path); err != nil {
+		log.Println("Failed to save the task snapshot:", err)
+	}
+}
+
+// commit records a write in the journal, if any, and applies it: tasks are stored and the task
+// with the id purged, if not 0, is removed. Nothing is applied if the journal fails.
+// The caller must hold the write lock.
+func (r *MemoryTaskRepository) commit(purged uint, tasks ...model.Task) error {
+	records := make([]fileRecord, 0, len(tasks)+1)
+	for i := range tasks {
+		records = append(records, fileRecord{Task: &tasks[i]})
+	}
+	if purged != 0 {
+		records = append(records, fileRecord{Purged: purged})
+	}
+	if err := writeJournal(r.journal, records...); err != nil {
+		return err
+	}
+	if purged != 0 {
+		delete(r.tasks, purged)
+		r.changes++
+	}
+	for _, task := range tasks {
+		r.store(task)
+	}
+	return nil
+}
+
+// store saves task and counts the change. The caller must hold the write lock.
+func (r *MemoryTaskRepository) store(task model.Task) {
+	r.tasks[task.Id] = task
+	r.changes++
+}
+
+// filter returns copies of the tasks matching keep, ordered by id. The caller must hold a lock.
+func (r *MemoryTaskRepository) filter(keep func(model.Task) bool) []model.Task {
+	tasks := make([]model.Task, 0)
+	for _, task := range r.tasks {
+		if keep(task) {
+			tasks = append(tasks, copyTask(task))
+		}
+	}
+	slices.SortFunc(tasks, func(a, b model.Task) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return tasks
+}
+
+// copyTask returns task with its own copies of the values its pointer and slice fields refer to.
+func copyTask(task model.Task) model.Task {
+	task.DueDate = clonePointer(task.DueDate)
+	task.ParentId = clonePointer(task.ParentId)
+	task.NextOccurrenceId = clonePointer(task.NextOccurrenceId)
+	task.Tags = slices.Clone(task.Tags)
+	return task
+}
+
+// clonePointer returns a pointer to a copy of the value p points to, or nil if p is nil.
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	value := *p
+	return &value
+}
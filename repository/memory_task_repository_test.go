@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"task_manager_go/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTaskRepository_Concurrent(t *testing.T) {
+	repo := NewMemoryTaskRepository()
+	created, err := repo.CreateTask(t.Context(), model.Task{Name: "shared", Status: model.StatusNew})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	updates := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.CreateTask(t.Context(), model.Task{Name: "task", Status: model.StatusNew})
+			assert.NoError(t, err)
+			if _, err := repo.UpdateTaskById(t.Context(), created.Id, model.Task{Name: "updated", Version: created.Version}); err == nil {
+				mu.Lock()
+				updates++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	tasks, err := repo.GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 51)
+	for i, task := range tasks {
+		assert.Equal(t, uint(i+1), task.Id)
+	}
+	assert.Equal(t, 1, updates)
+}
+
+func TestMemoryTaskRepository_IdsAndCopies(t *testing.T) {
+	repo := NewMemoryTaskRepository()
+	due := time.Now()
+	first, err := repo.CreateTask(t.Context(), model.Task{Name: "first", Status: model.StatusNew, DueDate: &due})
+	assert.NoError(t, err)
+	second, err := repo.CreateTask(t.Context(), model.Task{Name: "second", Status: model.StatusNew, ParentId: &first.Id})
+	assert.NoError(t, err)
+
+	due = due.Add(time.Hour)
+	read, err := repo.FindById(t.Context(), first.Id)
+	assert.NoError(t, err)
+	assert.False(t, read.DueDate.Equal(due))
+	*read.DueDate = due
+	read, err = repo.FindById(t.Context(), first.Id)
+	assert.NoError(t, err)
+	assert.False(t, read.DueDate.Equal(due))
+
+	assert.ErrorIs(t, repo.DeleteByID(t.Context(), first.Id, read.Version+1), model.ErrPreconditionFailed)
+	assert.NoError(t, repo.DeleteByID(t.Context(), first.Id, read.Version))
+	assert.NoError(t, repo.Purge(t.Context(), first.Id))
+	detached, err := repo.FindById(t.Context(), second.Id)
+	assert.NoError(t, err)
+	assert.Nil(t, detached.ParentId)
+
+	third, err := repo.CreateTask(t.Context(), model.Task{Name: "third", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Equal(t, second.Id+1, third.Id)
+	assert.Equal(t, 1, third.Occurrence)
+	assert.Equal(t, model.PriorityMedium, third.Priority)
+}
+
+func TestMemoryTaskRepository_Snapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	repo, err := LoadMemoryTaskRepository(path)
+	assert.NoError(t, err)
+
+	kept, err := repo.CreateTask(t.Context(), model.Task{Name: "kept", Status: model.StatusNew})
+	assert.NoError(t, err)
+	deleted, err := repo.CreateTask(t.Context(), model.Task{Name: "deleted", Status: model.StatusNew})
+	assert.NoError(t, err)
+	purged, err := repo.CreateTask(t.Context(), model.Task{Name: "purged", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.DeleteByID(t.Context(), deleted.Id, 0))
+	assert.NoError(t, repo.DeleteByID(t.Context(), purged.Id, 0))
+	assert.NoError(t, repo.Purge(t.Context(), purged.Id))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		repo.RunSnapshots(ctx, path, time.Hour)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	loaded, err := LoadMemoryTaskRepository(path)
+	assert.NoError(t, err)
+	task, err := loaded.FindById(t.Context(), kept.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "kept", task.Name)
+	assert.True(t, kept.CreatedAt.Equal(task.CreatedAt))
+	trash, err := loaded.FindDeleted(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.Equal(t, deleted.Id, trash[0].Id)
+
+	created, err := loaded.CreateTask(t.Context(), model.Task{Name: "new", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Equal(t, purged.Id+1, created.Id)
+}
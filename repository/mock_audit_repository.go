@@ -0,0 +1,9 @@
+package repository
+
+type MockAuditRepository struct {
+	*MemoryAuditRepository
+}
+
+func NewMockAuditRepository() *MockAuditRepository {
+	return &MockAuditRepository{MemoryAuditRepository: NewMemoryAuditRepository()}
+}
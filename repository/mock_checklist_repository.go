@@ -0,0 +1,9 @@
+package repository
+
+type MockChecklistRepository struct {
+	*MemoryChecklistRepository
+}
+
+func NewMockChecklistRepository() *MockChecklistRepository {
+	return &MockChecklistRepository{MemoryChecklistRepository: NewMemoryChecklistRepository()}
+}
@@ -0,0 +1,9 @@
+package repository
+
+type MockCommentRepository struct {
+	*MemoryCommentRepository
+}
+
+func NewMockCommentRepository() *MockCommentRepository {
+	return &MockCommentRepository{MemoryCommentRepository: NewMemoryCommentRepository()}
+}
@@ -0,0 +1,9 @@
+package repository
+
+type MockDependencyRepository struct {
+	*MemoryDependencyRepository
+}
+
+func NewMockDependencyRepository() *MockDependencyRepository {
+	return &MockDependencyRepository{MemoryDependencyRepository: NewMemoryDependencyRepository()}
+}
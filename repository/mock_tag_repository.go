@@ -0,0 +1,9 @@
+package repository
+
+type MockTagRepository struct {
+	*MemoryTagRepository
+}
+
+func NewMockTagRepository(tasks *MemoryTaskRepository) *MockTagRepository {
+	return &MockTagRepository{MemoryTagRepository: NewMemoryTagRepository(tasks)}
+}
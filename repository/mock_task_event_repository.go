@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"task_manager_go/model"
+	"time"
+)
+
+type MockTaskEventRepository struct {
+	*MockTaskRepository
+	events []model.TaskEvent
+}
+
+func NewMockTaskEventRepository() *MockTaskEventRepository {
+	return &MockTaskEventRepository{MockTaskRepository: NewMockTaskRepository()}
+}
+
+func (m *MockTaskEventRepository) CreateTask(ctx context.Context, task model.Task) (model.Task, error) {
+	created, err := m.MockTaskRepository.CreateTask(ctx, task)
+	if err != nil {
+		return model.Task{}, err
+	}
+	event, err := createdEvent(created, created.CreatedAt)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return created, m.appendEvents(ctx, nil, event)
+}
+
+func (m *MockTaskEventRepository) UpdateTaskById(ctx context.Context, id uint, task model.Task) (model.Task, error) {
+	before, err := m.FindById(ctx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	updated, err := m.MockTaskRepository.UpdateTaskById(ctx, id, task)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return updated, m.appendEvents(ctx, &before, changeEvents(before, updated)...)
+}
+
+func (m *MockTaskEventRepository) DeleteByID(ctx context.Context, id uint, version uint) error {
+	before, err := m.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := m.MockTaskRepository.DeleteByID(ctx, id, version); err != nil {
+		return err
+	}
+	return m.appendEvents(ctx, &before, deletedEvent(m.tasks[id]))
+}
+
+func (m *MockTaskEventRepository) Restore(ctx context.Context, id uint) (model.Task, error) {
+	before, err := m.FindDeletedById(ctx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	restored, err := m.MockTaskRepository.Restore(ctx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return restored, m.appendEvents(ctx, &before, restoredEvent(restored))
+}
+
+func (m *MockTaskEventRepository) Touch(ctx context.Context, id uint) (model.Task, error) {
+	before, exists := m.tasks[id]
+	if !exists {
+		return model.Task{}, notFound("task")
+	}
+	touched, err := m.MockTaskRepository.Touch(ctx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return touched, m.appendEvents(ctx, &before, changeEvents(before, touched)...)
+}
+
+func (m *MockTaskEventRepository) Purge(ctx context.Context, id uint) error {
+	before, err := m.FindDeletedById(ctx, id)
+	if err != nil {
+		return err
+	}
+	var linked []model.Task
+	for _, task := range m.tasks {
+		if task.Id != id && (task.ParentId != nil && *task.ParentId == id || task.NextOccurrenceId != nil && *task.NextOccurrenceId == id) {
+			linked = append(linked, task)
+		}
+	}
+	if err := m.MockTaskRepository.Purge(ctx, id); err != nil {
+		return err
+	}
+	for _, task := range linked {
+		if err := m.appendEvents(ctx, &task, changeEvents(task, m.tasks[task.Id])...); err != nil {
+			return err
+		}
+	}
+	return m.appendEvents(ctx, &before, purgedEvent(id, time.Now()))
+}
+
+func (m *MockTaskEventRepository) FindEvents(ctx context.Context, taskId uint) ([]model.TaskEvent, error) {
+	events := make([]model.TaskEvent, 0)
+	for _, event := range m.events {
+		if event.TaskId == taskId {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *MockTaskEventRepository) FindAt(ctx context.Context, id uint, at time.Time) (model.Task, error) {
+	events, _ := m.FindEvents(ctx, id)
+	events = slices.DeleteFunc(events, func(event model.TaskEvent) bool {
+		return event.At.After(at)
+	})
+	return replayAt(events)
+}
+
+func (m *MockTaskEventRepository) RebuildProjection(ctx context.Context) (int, error) {
+	events := slices.Clone(m.events)
+	slices.SortStableFunc(events, func(a, b model.TaskEvent) int {
+		return cmp.Compare(a.TaskId, b.TaskId)
+	})
+	logs := taskLogs(events)
+	for _, taskEvents := range logs {
+		task, exists, err := model.ReplayTask(taskEvents)
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			delete(m.tasks, taskEvents[0].TaskId)
+			continue
+		}
+		task.Tags = m.tasks[task.Id].Tags
+		m.tasks[task.Id] = task
+		m.nextId = max(m.nextId, task.Id+1)
+	}
+	return len(logs), nil
+}
+
+func (m *MockTaskEventRepository) appendEvents(ctx context.Context, before *model.Task, events ...model.TaskEvent) error {
+	taskEvents, _ := m.FindEvents(ctx, events[0].TaskId)
+	events, err := sequenceEvents(uint(len(taskEvents)), before, events)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		event.Id = uint(len(m.events)) + 1
+		m.events = append(m.events, event)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import "context"
+
+type MockTransactor struct {
+	repos Repositories
+}
+
+func NewMockTransactor(repos Repositories) *MockTransactor {
+	return &MockTransactor{repos: repos}
+}
+
+func (m *MockTransactor) InTransaction(ctx context.Context, fn func(repos Repositories) error) error {
+	return fn(m.repos)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"task_manager_go/model"
+
+	"gorm.io/gorm"
+)
+
+// TagRepositoryInterface defines the contract for tag storage and for attaching tags to tasks.
+type TagRepositoryInterface interface {
+	// CreateTag stores a new tag.
+	CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error)
+	// GetAll retrieves all tags ordered by name.
+	GetAll(ctx context.Context) ([]model.Tag, error)
+	// FindById retrieves a tag by its ID.
+	FindById(ctx context.Context, id uint) (model.Tag, error)
+	// FindByName retrieves a tag by its name.
+	FindByName(ctx context.Context, name string) (model.Tag, error)
+	// RenameTag changes the name of a tag on every task that carries it.
+	RenameTag(ctx context.Context, id uint, name string) (model.Tag, error)
+	// MergeTags moves every task carrying the source tag to the target tag and removes the source tag.
+	MergeTags(ctx context.Context, sourceId, targetId uint) error
+	// DeleteTag removes a tag and detaches it from every task.
+	DeleteTag(ctx context.Context, id uint) error
+	// FindByTask retrieves the tags attached to a task ordered by name.
+	FindByTask(ctx context.Context, taskId uint) ([]model.Tag, error)
+	// AttachTags attaches tags to a task, tags that are already attached are left as they are.
+	AttachTags(ctx context.Context, taskId uint, tagIds []uint) error
+	// DetachTag detaches a tag from a task.
+	DetachTag(ctx context.Context, taskId, tagId uint) error
+}
+
+// TagRepository implements TagRepositoryInterface using GORM associations of model.Task.
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new instance of TagRepository with the specified database connection.
+func NewTagRepository(db *gorm.DB) TagRepositoryInterface {
+	return &TagRepository{db: db}
+}
+
+// CreateTag implements the creation of a new tag in the database.
+func (r *TagRepository) CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	result := r.db.WithContext(ctx).Create(&tag)
+	return tag, translateError(result.Error, "tag")
+}
+
+// GetAll implements the retrieval of all tags from the database.
+func (r *TagRepository) GetAll(ctx context.Context) ([]model.Tag, error) {
+	var tags []model.Tag
+	result := r.db.WithContext(ctx).Order("name").Find(&tags)
+	return tags, result.Error
+}
+
+// FindById implements the retrieval of a tag by its ID from the database.
+func (r *TagRepository) FindById(ctx context.Context, id uint) (model.Tag, error) {
+	var tag model.Tag
+	result := r.db.WithContext(ctx).First(&tag, id)
+	return tag, translateError(result.Error, "tag")
+}
+
+// FindByName implements the retrieval of a tag by its name from the database.
+func (r *TagRepository) FindByName(ctx context.Context, name string) (model.Tag, error) {
+	var tag model.Tag
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&tag)
+	return tag, translateError(result.Error, "tag")
+}
+
+// RenameTag implements the renaming of a tag in the database.
+// Tasks reference tags by id, so the new name shows up on every task at once.
+func (r *TagRepository) RenameTag(ctx context.Context, id uint, name string) (model.Tag, error) {
+	result := r.db.WithContext(ctx).Model(&model.Tag{}).Where("id = ?", id).Update("name", name)
+	if result.Error != nil {
+		return model.Tag{}, translateError(result.Error, "tag")
+	}
+	return r.FindById(ctx, id)
+}
+
+// MergeTags implements the merging of two tags in a single transaction.
+func (r *TagRepository) MergeTags(ctx context.Context, sourceId, targetId uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO task_tags (task_id, tag_id)
+			SELECT task_id, ? FROM task_tags
+			WHERE tag_id = ? AND task_id NOT IN (SELECT task_id FROM task_tags WHERE tag_id = ?)`,
+			targetId, sourceId, targetId).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM task_tags WHERE tag_id = ?", sourceId).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.Tag{}, sourceId).Error
+	})
+}
+
+// DeleteTag implements the removal of a tag and its task associations from the database.
+func (r *TagRepository) DeleteTag(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM task_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	return translateError(err, "tag")
+}
+
+// FindByTask implements the retrieval of the tags attached to a task from the database.
+func (r *TagRepository) FindByTask(ctx context.Context, taskId uint) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.WithContext(ctx).Model(&model.Task{Id: taskId}).Order("tags.name").Association("Tags").Find(&tags)
+	return tags, err
+}
+
+// AttachTags implements attaching tags to a task through the Tags association.
+func (r *TagRepository) AttachTags(ctx context.Context, taskId uint, tagIds []uint) error {
+	tags := make([]model.Tag, len(tagIds))
+	for i, id := range tagIds {
+		tags[i] = model.Tag{Id: id}
+	}
+	return r.db.WithContext(ctx).Model(&model.Task{Id: taskId}).Omit("Tags.*").Association("Tags").Append(&tags)
+}
+
+// DetachTag implements detaching a tag from a task through the Tags association.
+func (r *TagRepository) DetachTag(ctx context.Context, taskId, tagId uint) error {
+	return r.db.WithContext(ctx).Model(&model.Task{Id: taskId}).Association("Tags").Delete(&model.Tag{Id: tagId})
+}
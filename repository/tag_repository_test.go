@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"task_manager_go/config"
+	"task_manager_go/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagRepository(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	tasks := NewTaskRepository(db)
+	repo := NewTagRepository(db)
+
+	first, err := tasks.CreateTask(t.Context(), model.Task{Name: "first", Status: model.StatusNew})
+	assert.NoError(t, err)
+	second, err := tasks.CreateTask(t.Context(), model.Task{Name: "second", Status: model.StatusNew})
+	assert.NoError(t, err)
+
+	backend, err := repo.CreateTag(t.Context(), model.Tag{Name: "backend"})
+	assert.NoError(t, err)
+	api, err := repo.CreateTag(t.Context(), model.Tag{Name: "api"})
+	assert.NoError(t, err)
+	_, err = repo.CreateTag(t.Context(), model.Tag{Name: "api"})
+	assert.Error(t, err)
+
+	assert.NoError(t, repo.AttachTags(t.Context(), first.Id, []uint{backend.Id, api.Id}))
+	assert.NoError(t, repo.AttachTags(t.Context(), first.Id, []uint{backend.Id}))
+	assert.NoError(t, repo.AttachTags(t.Context(), second.Id, []uint{api.Id}))
+
+	found, err := tasks.FindById(t.Context(), first.Id)
+	assert.NoError(t, err)
+	assert.Len(t, found.Tags, 2)
+	assert.Equal(t, "api", found.Tags[0].Name)
+
+	page, err := tasks.Find(t.Context(), model.TaskQuery{Tags: []string{"api", "backend"}, MatchAllTags: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	_, err = repo.RenameTag(t.Context(), api.Id, "rest-api")
+	assert.NoError(t, err)
+	page, err = tasks.Find(t.Context(), model.TaskQuery{Tags: []string{"rest-api"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+
+	assert.NoError(t, repo.MergeTags(t.Context(), backend.Id, api.Id))
+	tags, err := repo.FindByTask(t.Context(), first.Id)
+	assert.NoError(t, err)
+	assert.Len(t, tags, 1)
+
+	assert.NoError(t, repo.DetachTag(t.Context(), second.Id, api.Id))
+	assert.NoError(t, tasks.DeleteByID(t.Context(), first.Id, 0))
+	assert.NoError(t, repo.DeleteTag(t.Context(), api.Id))
+	all, err := repo.GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Empty(t, all)
+}
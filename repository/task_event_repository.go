@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"task_manager_go/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskEventRepositoryInterface defines the contract for a task storage that keeps every change as an event.
+// The current tasks are a projection of the events and are read like from any TaskRepositoryInterface.
+type TaskEventRepositoryInterface interface {
+	TaskRepositoryInterface
+	// FindEvents retrieves the events of a task in the order they were recorded.
+	FindEvents(ctx context.Context, taskId uint) ([]model.TaskEvent, error)
+	// FindAt retrieves a task as it was at the given moment by replaying its events up to that moment.
+	FindAt(ctx context.Context, id uint, at time.Time) (model.Task, error)
+	// RebuildProjection replaces the current tasks with the state replayed from their events.
+	// Returns the number of tasks that were replayed.
+	RebuildProjection(ctx context.Context) (int, error)
+}
+
+// TaskEventRepository implements TaskEventRepositoryInterface using GORM for database operations.
+// Changes are appended to the task_events table and applied to the tasks table in the same transaction,
+// so that reads, filters and the other repositories work on the projection just like with TaskRepository.
+type TaskEventRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskEventRepository creates a new instance of TaskEventRepository with the specified database connection.
+func NewTaskEventRepository(db *gorm.DB) TaskEventRepositoryInterface {
+	return &TaskEventRepository{db: db}
+}
+
+// projection returns the repository of the projected tasks in db.
+func projection(db *gorm.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+// CreateTask implements the creation of a new task by recording a TaskCreated event with all of its fields.
+func (r *TaskEventRepository) CreateTask(ctx context.Context, task model.Task) (model.Task, error) {
+	var created model.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		stored, err := projection(tx).CreateTask(ctx, task)
+		if err != nil {
+			return err
+		}
+		if created, err = projection(tx).FindById(ctx, stored.Id); err != nil {
+			return err
+		}
+		event, err := createdEvent(created, created.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return appendEvents(tx, nil, event)
+	})
+	return created, err
+}
+
+// GetAll implements the retrieval of all tasks from the projection.
+func (r *TaskEventRepository) GetAll(ctx context.Context) ([]model.Task, error) {
+	return projection(r.db).GetAll(ctx)
+}
+
+// Find implements the retrieval of a filtered, sorted and paginated list of tasks from the projection.
+func (r *TaskEventRepository) Find(ctx context.Context, query model.TaskQuery) (model.TaskPage, error) {
+	return projection(r.db).Find(ctx, query)
+}
+
+// CountByStatus implements counting the tasks of the projection by status.
+func (r *TaskEventRepository) CountByStatus(ctx context.Context) (map[model.Status]int64, error) {
+	return projection(r.db).CountByStatus(ctx)
+}
+
+// FindById implements the retrieval of a task by its ID from the projection.
+func (r *TaskEventRepository) FindById(ctx context.Context, id uint) (model.Task, error) {
+	return projection(r.db).FindById(ctx, id)
+}
+
+// FindChildren implements the retrieval of the direct subtasks of a task from the projection.
+func (r *TaskEventRepository) FindChildren(ctx context.Context, parentId uint) ([]model.Task, error) {
+	return projection(r.db).FindChildren(ctx, parentId)
+}
+
+// UpdateTaskById implements the update of an existing task by recording a TaskRenamed event for a new name,
+// a StatusChanged event for a new status and a TaskUpdated event for the other changed fields.
+// An update that changes nothing is recorded as an empty TaskUpdated event, since it still increments the version.
+func (r *TaskEventRepository) UpdateTaskById(ctx context.Context, id uint, task model.Task) (model.Task, error) {
+	var updated model.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := projection(tx).FindById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if task.Version != 0 && task.Version != before.Version {
+			return errVersionMismatch
+		}
+		task.Version = before.Version
+		if updated, err = projection(tx).UpdateTaskById(ctx, id, task); err != nil {
+			return err
+		}
+		return appendEvents(tx, &before, changeEvents(before, updated)...)
+	})
+	return updated, err
+}
+
+// DeleteByID implements moving a task to the trash by recording a TaskDeleted event.
+func (r *TaskEventRepository) DeleteByID(ctx context.Context, id uint, version uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := projection(tx).FindById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := projection(tx).DeleteByID(ctx, id, version); err != nil {
+			return err
+		}
+		deleted, err := projection(tx).FindDeletedById(ctx, id)
+		if err != nil {
+			return err
+		}
+		return appendEvents(tx, &before, deletedEvent(deleted))
+	})
+}
+
+// FindDeleted implements the retrieval of the tasks in the trash from the projection.
+func (r *TaskEventRepository) FindDeleted(ctx context.Context) ([]model.Task, error) {
+	return projection(r.db).FindDeleted(ctx)
+}
+
+// FindDeletedById implements the retrieval of a task in the trash by its ID from the projection.
+func (r *TaskEventRepository) FindDeletedById(ctx context.Context, id uint) (model.Task, error) {
+	return projection(r.db).FindDeletedById(ctx, id)
+}
+
+// Restore implements taking a task out of the trash by recording a TaskRestored event.
+func (r *TaskEventRepository) Restore(ctx context.Context, id uint) (model.Task, error) {
+	var restored model.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := projection(tx).FindDeletedById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if restored, err = projection(tx).Restore(ctx, id); err != nil {
+			return err
+		}
+		return appendEvents(tx, &before, restoredEvent(restored))
+	})
+	return restored, err
+}
+
+// Touch implements incrementing the version of a task, in the trash or not, by recording an empty TaskUpdated event.
+func (r *TaskEventRepository) Touch(ctx context.Context, id uint) (model.Task, error) {
+	var touched model.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := projection(tx).findAny(ctx, id)
+		if err != nil {
+			return err
+		}
+		if touched, err = projection(tx).Touch(ctx, id); err != nil {
+			return err
+		}
+		return appendEvents(tx, &before, changeEvents(before, touched)...)
+	})
+	return touched, err
+}
+
+// Purge implements the permanent removal of a task in the trash by recording a TaskPurged event.
+// The events of the task are kept, and the tasks detached from it record a TaskUpdated event.
+func (r *TaskEventRepository) Purge(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := projection(tx).FindDeletedById(ctx, id)
+		if err != nil {
+			return err
+		}
+		var linked []model.Task
+		err = tx.Unscoped().Where("id <> ? AND (parent_id = ? OR next_occurrence_id = ?)", id, id, id).Order("id").Find(&linked).Error
+		if err != nil {
+			return err
+		}
+		if err := projection(tx).Purge(ctx, id); err != nil {
+			return err
+		}
+		for _, task := range linked {
+			var detached model.Task
+			if err := tx.Unscoped().First(&detached, task.Id).Error; err != nil {
+				return err
+			}
+			if err := appendEvents(tx, &task, changeEvents(task, detached)...); err != nil {
+				return err
+			}
+		}
+		return appendEvents(tx, &before, purgedEvent(id, time.Now()))
+	})
+}
+
+// FindEvents implements the retrieval of the events of a task ordered by sequence.
+func (r *TaskEventRepository) FindEvents(ctx context.Context, taskId uint) ([]model.TaskEvent, error) {
+	var events []model.TaskEvent
+	result := r.db.WithContext(ctx).Where("task_id = ?", taskId).Order("sequence").Find(&events)
+	return events, result.Error
+}
+
+// FindAt implements the retrieval of a task at a moment by replaying the events recorded until then.
+// Returns a not found error if the task was not created yet or already purged at that moment.
+func (r *TaskEventRepository) FindAt(ctx context.Context, id uint, at time.Time) (model.Task, error) {
+	var events []model.TaskEvent
+	if err := r.db.WithContext(ctx).Where("task_id = ? AND at <= ?", id, at).Order("sequence").Find(&events).Error; err != nil {
+		return model.Task{}, err
+	}
+	return replayAt(events)
+}
+
+// RebuildProjection implements replaying every event log into the tasks table in a single transaction.
+// Tasks without events, stored before the event log was used, are left as they are.
+func (r *TaskEventRepository) RebuildProjection(ctx context.Context) (int, error) {
+	var logs [][]model.TaskEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var events []model.TaskEvent
+		if err := tx.Order("task_id").Order("sequence").Find(&events).Error; err != nil {
+			return err
+		}
+		logs = taskLogs(events)
+		for _, taskEvents := range logs {
+			task, exists, err := model.ReplayTask(taskEvents)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				err = tx.Unscoped().Select("Tags").Delete(&model.Task{Id: taskEvents[0].TaskId}).Error
+			} else {
+				err = tx.Session(&gorm.Session{SkipHooks: true}).Unscoped().Omit(clause.Associations).Save(&task).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(logs), err
+}
+
+// appendEvents numbers the events of a task and appends them to its log.
+// before is the task the events change, nil for a new task.
+// Two concurrent changes of a task cannot both take the same sequence numbers.
+func appendEvents(tx *gorm.DB, before *model.Task, events ...model.TaskEvent) error {
+	var last uint
+	err := tx.Model(&model.TaskEvent{}).Where("task_id = ?", events[0].TaskId).Select("COALESCE(MAX(sequence), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	events, err = sequenceEvents(last, before, events)
+	if err != nil {
+		return err
+	}
+	err = tx.Create(&events).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errVersionMismatch
+	}
+	return err
+}
+
+// sequenceEvents numbers the events of a task following the last recorded sequence number.
+// A task stored before the event log was used has no events yet, so its log is started
+// with a TaskCreated event holding the state before the change, dated when the task was created.
+func sequenceEvents(last uint, before *model.Task, events []model.TaskEvent) ([]model.TaskEvent, error) {
+	if last == 0 && before != nil {
+		at := before.CreatedAt
+		if at.IsZero() || at.After(events[0].At) {
+			at = events[0].At
+		}
+		created, err := createdEvent(*before, at)
+		if err != nil {
+			return nil, err
+		}
+		events = append([]model.TaskEvent{created}, events...)
+	}
+	for i := range events {
+		events[i].Sequence = last + uint(i) + 1
+	}
+	return events, nil
+}
+
+// createdEvent returns the TaskCreated event holding every field of task, except for its tags.
+func createdEvent(task model.Task, at time.Time) (model.TaskEvent, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return model.TaskEvent{}, err
+	}
+	var fields model.TaskEventFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return model.TaskEvent{}, err
+	}
+	delete(fields, "Tags")
+	return model.TaskEvent{TaskId: task.Id, Type: model.TaskCreated, Fields: fields, At: at}, nil
+}
+
+// changeEvents returns the events turning before into after.
+// Every event also sets the version and update time of after.
+func changeEvents(before, after model.Task) []model.TaskEvent {
+	event := func(eventType model.TaskEventType, fields model.TaskEventFields) model.TaskEvent {
+		fields["Version"] = after.Version
+		fields["UpdatedAt"] = after.UpdatedAt
+		return model.TaskEvent{TaskId: after.Id, Type: eventType, Fields: fields, At: after.UpdatedAt}
+	}
+	var events []model.TaskEvent
+	updated := model.TaskEventFields{}
+	for _, change := range model.DiffTasks(&before, &after) {
+		switch change.Field {
+		case "Name":
+			events = append(events, event(model.TaskRenamed, model.TaskEventFields{"Name": change.After}))
+		case "Status":
+			events = append(events, event(model.StatusChanged, model.TaskEventFields{"Status": change.After}))
+		default:
+			updated[change.Field] = change.After
+		}
+	}
+	if len(updated) > 0 || len(events) == 0 {
+		events = append(events, event(model.TaskUpdated, updated))
+	}
+	return events
+}
+
+// deletedEvent returns the TaskDeleted event of a task that was moved to the trash.
+func deletedEvent(task model.Task) model.TaskEvent {
+	return model.TaskEvent{
+		TaskId: task.Id,
+		Type:   model.TaskDeleted,
+		Fields: model.TaskEventFields{"DeletedAt": task.DeletedAt},
+		At:     task.DeletedAt.Time,
+	}
+}
+
+// restoredEvent returns the TaskRestored event of a task that was taken out of the trash.
+func restoredEvent(task model.Task) model.TaskEvent {
+	return model.TaskEvent{
+		TaskId: task.Id,
+		Type:   model.TaskRestored,
+		Fields: model.TaskEventFields{"DeletedAt": nil, "Version": task.Version, "UpdatedAt": task.UpdatedAt},
+		At:     task.UpdatedAt,
+	}
+}
+
+// purgedEvent returns the TaskPurged event of a task that was removed at the given moment.
+func purgedEvent(id uint, at time.Time) model.TaskEvent {
+	return model.TaskEvent{TaskId: id, Type: model.TaskPurged, Fields: model.TaskEventFields{}, At: at}
+}
+
+// taskLogs splits events sorted by task and sequence into the logs of the individual tasks.
+func taskLogs(events []model.TaskEvent) [][]model.TaskEvent {
+	var logs [][]model.TaskEvent
+	for _, event := range events {
+		if n := len(logs); n == 0 || logs[n-1][0].TaskId != event.TaskId {
+			logs = append(logs, nil)
+		}
+		logs[len(logs)-1] = append(logs[len(logs)-1], event)
+	}
+	return logs
+}
+
+// replayAt returns the state of a task described by the events recorded until some moment.
+func replayAt(events []model.TaskEvent) (model.Task, error) {
+	if len(events) == 0 {
+		return model.Task{}, notFound("task")
+	}
+	task, exists, err := model.ReplayTask(events)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if !exists {
+		return model.Task{}, notFound("task")
+	}
+	return task, nil
+}
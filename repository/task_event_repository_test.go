@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"task_manager_go/config"
+	"task_manager_go/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskEventRepository_Events(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskEventRepository(db)
+
+	created, err := repo.CreateTask(t.Context(), model.Task{Name: "Task", Status: model.StatusNew})
+	assert.NoError(t, err)
+	updated, err := repo.UpdateTaskById(t.Context(), created.Id, model.Task{Name: "Renamed", Status: model.StatusInProgress, Priority: model.PriorityHigh})
+	assert.NoError(t, err)
+	assert.Equal(t, created.Version+1, updated.Version)
+	_, err = repo.UpdateTaskById(t.Context(), created.Id, model.Task{Name: "Stale", Version: created.Version})
+	assert.ErrorIs(t, err, model.ErrPreconditionFailed)
+	assert.NoError(t, repo.DeleteByID(t.Context(), created.Id, 0))
+	touched, err := repo.Touch(t.Context(), created.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, updated.Version+1, touched.Version)
+
+	events, err := repo.FindEvents(t.Context(), created.Id)
+	assert.NoError(t, err)
+	types := make([]model.TaskEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []model.TaskEventType{model.TaskCreated, model.TaskRenamed, model.StatusChanged, model.TaskUpdated, model.TaskDeleted, model.TaskUpdated}, types)
+
+	original, err := repo.FindAt(t.Context(), created.Id, created.CreatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "Task", original.Name)
+	assert.Equal(t, model.PriorityMedium, original.Priority)
+	deleted, err := repo.FindAt(t.Context(), created.Id, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", deleted.Name)
+	assert.True(t, deleted.DeletedAt.Valid)
+	_, err = repo.FindAt(t.Context(), created.Id, created.CreatedAt.Add(-time.Second))
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestTaskEventRepository_TaskBeforeEventLog(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskEventRepository(db)
+
+	created, err := NewTaskRepository(db).CreateTask(t.Context(), model.Task{Name: "Legacy", Status: model.StatusNew})
+	assert.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	_, err = repo.UpdateTaskById(t.Context(), created.Id, model.Task{Name: "Renamed", Status: model.StatusNew})
+	assert.NoError(t, err)
+
+	events, err := repo.FindEvents(t.Context(), created.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TaskCreated, events[0].Type)
+	assert.True(t, events[0].At.Equal(created.CreatedAt))
+	legacy, err := repo.FindAt(t.Context(), created.Id, created.CreatedAt.Add(5*time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, "Legacy", legacy.Name)
+}
+
+func TestTaskEventRepository_RebuildProjection(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskEventRepository(db)
+
+	parent, err := repo.CreateTask(t.Context(), model.Task{Name: "Parent", Status: model.StatusNew})
+	assert.NoError(t, err)
+	child, err := repo.CreateTask(t.Context(), model.Task{Name: "Child", Status: model.StatusNew, ParentId: &parent.Id})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.DeleteByID(t.Context(), parent.Id, 0))
+	assert.NoError(t, repo.Purge(t.Context(), parent.Id))
+	assert.NoError(t, db.Model(&model.Task{}).Where("id = ?", child.Id).UpdateColumn("name", "Corrupted").Error)
+
+	rebuilt, err := repo.RebuildProjection(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rebuilt)
+	replayed, err := repo.FindById(t.Context(), child.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Child", replayed.Name)
+	assert.Nil(t, replayed.ParentId)
+	_, err = repo.FindDeletedById(t.Context(), parent.Id)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
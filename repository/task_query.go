@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"task_manager_go/model"
+)
+
+// queryTasks applies the filters, ordering and pagination of query to an in-memory list of tasks.
+// It mirrors the semantics of TaskRepository.Find for repositories that do not use a database.
+func queryTasks(tasks []model.Task, query model.TaskQuery) model.TaskPage {
+	matched := make([]model.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if matchesTaskQuery(task, query) {
+			matched = append(matched, task)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return lessTask(matched[i], matched[j], query)
+	})
+
+	page := model.TaskPage{Total: int64(len(matched)), Limit: query.Limit, Offset: query.Offset}
+	if query.After != nil {
+		matched = slices.DeleteFunc(matched, func(task model.Task) bool {
+			return !isAfterCursor(task, *query.After, query.SortDesc)
+		})
+	}
+	start := min(max(query.Offset, 0), len(matched))
+	end := len(matched)
+	if query.Limit > 0 {
+		end = min(start+query.Limit+1, end)
+	}
+	page.Tasks, page.NextCursor = trimPage(matched[start:end], query)
+	return page
+}
+
+// trimPage cuts the extra lookahead row off a page fetched with Limit+1 rows.
+// When the row was present and the listing is sorted by date, it returns the cursor of the next page.
+func trimPage(tasks []model.Task, query model.TaskQuery) ([]model.Task, *model.TaskCursor) {
+	if query.Limit <= 0 || len(tasks) <= query.Limit {
+		return tasks, nil
+	}
+	tasks = tasks[:query.Limit]
+	if query.SortBy != model.SortByDate {
+		return tasks, nil
+	}
+	return tasks, model.CursorAfter(tasks[len(tasks)-1])
+}
+
+// isAfterCursor reports whether task follows cursor in a listing sorted by (Date, Id).
+func isAfterCursor(task model.Task, cursor model.TaskCursor, desc bool) bool {
+	order := task.Date.Compare(cursor.Date)
+	if order == 0 {
+		order = cmp.Compare(task.Id, cursor.Id)
+	}
+	if desc {
+		return order < 0
+	}
+	return order > 0
+}
+
+// matchesTaskQuery reports whether task passes every filter of query.
+func matchesTaskQuery(task model.Task, query model.TaskQuery) bool {
+	if query.Status != "" && task.Status != query.Status {
+		return false
+	}
+	if query.NameContains != "" && !strings.Contains(strings.ToLower(task.Name), strings.ToLower(query.NameContains)) {
+		return false
+	}
+	if query.CreatedAfter != nil && !task.Date.After(*query.CreatedAfter) {
+		return false
+	}
+	if query.CreatedBefore != nil && !task.Date.Before(*query.CreatedBefore) {
+		return false
+	}
+	if query.Priority != 0 && task.Priority != query.Priority {
+		return false
+	}
+	if query.Assignee != "" && task.Assignee != query.Assignee {
+		return false
+	}
+	if query.DueAfter != nil && (task.DueDate == nil || !task.DueDate.After(*query.DueAfter)) {
+		return false
+	}
+	if query.DueBefore != nil && (task.DueDate == nil || !task.DueDate.Before(*query.DueBefore)) {
+		return false
+	}
+	if len(query.Tags) > 0 && !matchesTags(task, query.Tags, query.MatchAllTags) {
+		return false
+	}
+	return true
+}
+
+// matchesTags reports whether task carries any, or with matchAll every one, of the named tags.
+func matchesTags(task model.Task, names []string, matchAll bool) bool {
+	for _, name := range names {
+		carried := slices.ContainsFunc(task.Tags, func(tag model.Tag) bool {
+			return tag.Name == name
+		})
+		if carried && !matchAll {
+			return true
+		}
+		if !carried && matchAll {
+			return false
+		}
+	}
+	return matchAll
+}
+
+// lessTask reports whether a sorts before b under the ordering of query.
+// Tasks without a due date and ties are ordered just like in the database implementation.
+func lessTask(a, b model.Task, query model.TaskQuery) bool {
+	if query.SortBy == model.SortByDueDate && (a.DueDate == nil) != (b.DueDate == nil) {
+		return b.DueDate == nil
+	}
+	order := compareTasks(a, b, query.SortBy)
+	if order == 0 {
+		order = cmp.Compare(a.Id, b.Id)
+	}
+	if query.SortDesc {
+		return order > 0
+	}
+	return order < 0
+}
+
+// compareTasks compares a and b by the given sort field.
+func compareTasks(a, b model.Task, field string) int {
+	switch field {
+	case model.SortByName:
+		return strings.Compare(a.Name, b.Name)
+	case model.SortByStatus:
+		return strings.Compare(string(a.Status), string(b.Status))
+	case model.SortByDate:
+		return a.Date.Compare(b.Date)
+	case model.SortByPriority:
+		return cmp.Compare(a.Priority, b.Priority)
+	case model.SortByDueDate:
+		if a.DueDate == nil || b.DueDate == nil {
+			return 0
+		}
+		return a.DueDate.Compare(*b.DueDate)
+	case model.SortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case model.SortByUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return cmp.Compare(a.Id, b.Id)
+	}
+}
+
+// uniqueStrings returns values without duplicates, keeping the first occurrence of each.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
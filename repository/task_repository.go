@@ -1,23 +1,48 @@
 package repository
 
 import (
+	"context"
+	"strings"
 	"task_manager_go/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TaskRepositoryInterface defines the contract for task data storage operations.
+// Every operation takes the context of the request it serves; the database implementations cancel their queries with it.
 type TaskRepositoryInterface interface {
 	// CreateTask stores a new task in the database.
-	CreateTask(task model.Task) (model.Task, error)
+	CreateTask(ctx context.Context, task model.Task) (model.Task, error)
 	// GetAll retrieves all tasks from the database.
-	GetAll() ([]model.Task, error)
+	GetAll(ctx context.Context) ([]model.Task, error)
+	// Find retrieves a filtered, sorted and paginated list of tasks.
+	Find(ctx context.Context, query model.TaskQuery) (model.TaskPage, error)
+	// CountByStatus counts the tasks outside the trash by status; statuses no task has are left out.
+	CountByStatus(ctx context.Context) (map[model.Status]int64, error)
 	// FindById retrieves a task by its ID from the database.
-	FindById(id uint) (model.Task, error)
-	// UpdateTaskById updates an existing task in the database.
-	UpdateTaskById(id uint, task model.Task) (model.Task, error)
-	// DeleteByID removes a task from the database by its ID.
-	DeleteByID(id uint) error
+	FindById(ctx context.Context, id uint) (model.Task, error)
+	// FindChildren retrieves the direct subtasks of a task.
+	FindChildren(ctx context.Context, parentId uint) ([]model.Task, error)
+	// UpdateTaskById updates an existing task in the database and increments its version.
+	// A task with a non-zero Version is only updated if the stored task still has that version.
+	UpdateTaskById(ctx context.Context, id uint, task model.Task) (model.Task, error)
+	// DeleteByID moves a task to the trash by its ID.
+	// A non-zero version only deletes the task if the stored task still has that version.
+	// Deleted tasks are left out of every other query until they are restored.
+	DeleteByID(ctx context.Context, id uint, version uint) error
+	// FindDeleted retrieves the tasks in the trash, most recently deleted first.
+	FindDeleted(ctx context.Context) ([]model.Task, error)
+	// FindDeletedById retrieves a task in the trash by its ID.
+	FindDeletedById(ctx context.Context, id uint) (model.Task, error)
+	// Restore takes a task out of the trash and increments its version.
+	Restore(ctx context.Context, id uint) (model.Task, error)
+	// Touch increments the version of a task, in the trash or not, without changing its fields,
+	// for changes of what belongs to the task, such as its tags.
+	Touch(ctx context.Context, id uint) (model.Task, error)
+	// Purge permanently removes a task in the trash together with its tags.
+	// Tasks referring to it as parent or next occurrence are detached from it.
+	Purge(ctx context.Context, id uint) error
 }
 
 // TaskRepository implements TaskRepositoryInterface using GORM for database operations.
@@ -31,41 +56,287 @@ func NewTaskRepository(db *gorm.DB) TaskRepositoryInterface {
 }
 
 // CreateTask implements the creation of a new task in the database.
-func (r *TaskRepository) CreateTask(task model.Task) (model.Task, error) {
-	result := r.db.Create(&task)
+// Tags are not stored with the task, they are attached through the TagRepositoryInterface.
+func (r *TaskRepository) CreateTask(ctx context.Context, task model.Task) (model.Task, error) {
+	task.Tags = nil
+	task.Version = 1
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(&task)
 	return task, result.Error
 }
 
 // GetAll implements the retrieval of all tasks from the database.
-func (r *TaskRepository) GetAll() ([]model.Task, error) {
+func (r *TaskRepository) GetAll(ctx context.Context) ([]model.Task, error) {
 	var tasks []model.Task
-	result := r.db.Find(&tasks)
+	result := r.db.WithContext(ctx).Scopes(withTags).Find(&tasks)
 	return tasks, result.Error
 }
 
+// Find implements the retrieval of a filtered, sorted and paginated list of tasks.
+// The total count ignores Limit, Offset and After so that clients can page through the result.
+// Listings sorted by date use keyset pagination on (date, id) and report the next cursor.
+func (r *TaskRepository) Find(ctx context.Context, query model.TaskQuery) (model.TaskPage, error) {
+	page := model.TaskPage{Limit: query.Limit, Offset: query.Offset}
+
+	if err := r.db.WithContext(ctx).Model(&model.Task{}).Scopes(taskFilters(query)).Count(&page.Total).Error; err != nil {
+		return model.TaskPage{}, err
+	}
+
+	db := r.db.WithContext(ctx).Scopes(taskFilters(query), taskKeyset(query), taskOrder(query), withTags)
+	if query.Limit > 0 {
+		// one extra row tells whether another page follows
+		db = db.Limit(query.Limit + 1)
+	}
+	if query.Offset > 0 {
+		db = db.Offset(query.Offset)
+	}
+	if err := db.Find(&page.Tasks).Error; err != nil {
+		return model.TaskPage{}, err
+	}
+	page.Tasks, page.NextCursor = trimPage(page.Tasks, query)
+	return page, nil
+}
+
+// CountByStatus implements counting the tasks by status in a single grouped query.
+func (r *TaskRepository) CountByStatus(ctx context.Context) (map[model.Status]int64, error) {
+	var rows []struct {
+		Status model.Status
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.Task{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[model.Status]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// taskFilters returns a scope restricting a query to the tasks matching the filters of query.
+func taskFilters(query model.TaskQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Status != "" {
+			db = db.Where("status = ?", query.Status)
+		}
+		if query.NameContains != "" {
+			db = db.Where(`LOWER(name) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(query.NameContains))+"%")
+		}
+		if query.CreatedAfter != nil {
+			db = db.Where("date > ?", *query.CreatedAfter)
+		}
+		if query.CreatedBefore != nil {
+			db = db.Where("date < ?", *query.CreatedBefore)
+		}
+		if query.Priority != 0 {
+			db = db.Where("priority = ?", query.Priority)
+		}
+		if query.Assignee != "" {
+			db = db.Where("assignee = ?", query.Assignee)
+		}
+		if query.DueAfter != nil {
+			db = db.Where("due_date > ?", *query.DueAfter)
+		}
+		if query.DueBefore != nil {
+			db = db.Where("due_date < ?", *query.DueBefore)
+		}
+		if len(query.Tags) > 0 {
+			tagged := db.Session(&gorm.Session{NewDB: true}).
+				Table("task_tags").
+				Select("task_tags.task_id").
+				Joins("JOIN tags ON tags.id = task_tags.tag_id").
+				Where("tags.name IN ?", query.Tags)
+			if query.MatchAllTags {
+				tagged = tagged.Group("task_tags.task_id").Having("COUNT(DISTINCT tags.id) = ?", len(uniqueStrings(query.Tags)))
+			}
+			db = db.Where("id IN (?)", tagged)
+		}
+		return db
+	}
+}
+
+// taskKeyset returns a scope restricting a date-sorted query to the tasks after query.After.
+func taskKeyset(query model.TaskQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.After == nil {
+			return db
+		}
+		op := ">"
+		if query.SortDesc {
+			op = "<"
+		}
+		return db.Where("(date "+op+" ? OR (date = ? AND id "+op+" ?))", query.After.Date, query.After.Date, query.After.Id)
+	}
+}
+
+// taskOrder returns a scope ordering a query by the sort field of query.
+// Tasks without a due date come last in both directions when sorting by due date.
+// Ties are broken by id so that pagination is deterministic.
+func taskOrder(query model.TaskQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		direction := "ASC"
+		if query.SortDesc {
+			direction = "DESC"
+		}
+		column := model.SortById
+		if model.IsTaskSortField(query.SortBy) {
+			column = query.SortBy
+		}
+		if column == model.SortByDueDate {
+			db = db.Order("due_date IS NULL")
+		}
+		db = db.Order(column + " " + direction)
+		if column != model.SortById {
+			db = db.Order("id " + direction)
+		}
+		return db
+	}
+}
+
+// withTags is a scope loading the tags of the queried tasks ordered by name.
+func withTags(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tags.name")
+	})
+}
+
+// escapeLike escapes the LIKE wildcards in s so that it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // FindById implements the retrieval of a task by its ID from the database.
-func (r *TaskRepository) FindById(id uint) (model.Task, error) {
+func (r *TaskRepository) FindById(ctx context.Context, id uint) (model.Task, error) {
 	var task model.Task
-	result := r.db.First(&task, id)
-	return task, result.Error
+	result := r.db.WithContext(ctx).Scopes(withTags).First(&task, id)
+	return task, translateError(result.Error, "task")
+}
+
+// FindChildren implements the retrieval of the direct subtasks of a task ordered by id.
+func (r *TaskRepository) FindChildren(ctx context.Context, parentId uint) ([]model.Task, error) {
+	var tasks []model.Task
+	result := r.db.WithContext(ctx).Scopes(withTags).Where("parent_id = ?", parentId).Order("id").Find(&tasks)
+	return tasks, result.Error
 }
 
 // UpdateTaskById implements the update of an existing task in the database.
-func (r *TaskRepository) UpdateTaskById(id uint, task model.Task) (model.Task, error) {
-	var updatedTask model.Task
-	result := r.db.Model(&updatedTask).Where("Id = ?", id).Updates(map[string]interface{}{
-		"name":   task.Name,
-		"status": task.Status,
-	}).First(&updatedTask)
-	return updatedTask, result.Error
+// The version is checked in the same statement that increments it, so concurrent updates cannot both succeed.
+// Tags are left untouched, they are changed through the TagRepositoryInterface.
+func (r *TaskRepository) UpdateTaskById(ctx context.Context, id uint, task model.Task) (model.Task, error) {
+	db := r.db.WithContext(ctx).Model(&model.Task{}).Where("id = ?", id)
+	if task.Version != 0 {
+		db = db.Where("version = ?", task.Version)
+	}
+	result := db.Updates(map[string]interface{}{
+		"name":               task.Name,
+		"description":        task.Description,
+		"status":             task.Status,
+		"priority":           task.Priority,
+		"due_date":           task.DueDate,
+		"assignee":           task.Assignee,
+		"parent_id":          task.ParentId,
+		"recurrence":         task.Recurrence,
+		"time_zone":          task.TimeZone,
+		"occurrence":         task.Occurrence,
+		"next_occurrence_id": task.NextOccurrenceId,
+		"version":            gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return model.Task{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.FindById(ctx, id); err != nil {
+			return model.Task{}, err
+		}
+		return model.Task{}, errVersionMismatch
+	}
+	return r.FindById(ctx, id)
+}
+
+// DeleteByID implements moving a task to the trash by setting its DeletedAt.
+// The version is checked in the same statement, so a concurrent update cannot slip in between.
+// Its tags stay attached so that a restored task gets them back.
+func (r *TaskRepository) DeleteByID(ctx context.Context, id uint, version uint) error {
+	db := r.db.WithContext(ctx).Where("id = ?", id)
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+	result := db.Delete(&model.Task{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.FindById(ctx, id); err != nil {
+			return err
+		}
+		return errVersionMismatch
+	}
+	return nil
 }
 
-// DeleteByID implements the removal of a task from the database by its ID.
-func (r *TaskRepository) DeleteByID(id uint) error {
+// FindDeleted implements the retrieval of the tasks in the trash.
+func (r *TaskRepository) FindDeleted(ctx context.Context) ([]model.Task, error) {
+	var tasks []model.Task
+	result := r.db.WithContext(ctx).Unscoped().Scopes(withTags).Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Order("id").Find(&tasks)
+	return tasks, result.Error
+}
+
+// FindDeletedById implements the retrieval of a task in the trash by its ID.
+func (r *TaskRepository) FindDeletedById(ctx context.Context, id uint) (model.Task, error) {
 	var task model.Task
-	if err := r.db.First(&task, id).Error; err != nil {
-		return err
+	result := r.db.WithContext(ctx).Unscoped().Scopes(withTags).Where("deleted_at IS NOT NULL").First(&task, id)
+	return task, translateError(result.Error, "deleted task")
+}
+
+// Restore implements taking a task out of the trash by clearing its DeletedAt.
+func (r *TaskRepository) Restore(ctx context.Context, id uint) (model.Task, error) {
+	result := r.db.WithContext(ctx).Unscoped().Model(&model.Task{}).Where("id = ? AND deleted_at IS NOT NULL", id).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return model.Task{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return model.Task{}, notFound("deleted task")
+	}
+	return r.FindById(ctx, id)
+}
+
+// Touch implements incrementing the version of a task, in the trash or not, in the database.
+func (r *TaskRepository) Touch(ctx context.Context, id uint) (model.Task, error) {
+	result := r.db.WithContext(ctx).Unscoped().Model(&model.Task{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return model.Task{}, result.Error
 	}
-	result := r.db.Delete(&task)
-	return result.Error
+	if result.RowsAffected == 0 {
+		return model.Task{}, notFound("task")
+	}
+	return r.findAny(ctx, id)
+}
+
+// findAny retrieves a task by its ID, in the trash or not.
+func (r *TaskRepository) findAny(ctx context.Context, id uint) (model.Task, error) {
+	var task model.Task
+	result := r.db.WithContext(ctx).Unscoped().Scopes(withTags).First(&task, id)
+	return task, translateError(result.Error, "task")
+}
+
+// Purge implements the permanent removal of a task in the trash in a single transaction.
+func (r *TaskRepository) Purge(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task model.Task
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&task, id).Error; err != nil {
+			return translateError(err, "deleted task")
+		}
+		if err := tx.Unscoped().Model(&model.Task{}).Where("parent_id = ?", id).Update("parent_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&model.Task{}).Where("next_occurrence_id = ?", id).Update("next_occurrence_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Select("Tags").Delete(&task).Error
+	})
 }
@@ -10,7 +10,7 @@ import (
 )
 
 func TestNewTaskRepository(t *testing.T) {
-	db, cleanup := config.InitTestDBWithDocker()
+	db, cleanup := config.InitTestDB()
 	defer cleanup()
 	repo := NewTaskRepository(db)
 	assert.NotNil(t, repo)
@@ -18,7 +18,7 @@ func TestNewTaskRepository(t *testing.T) {
 }
 
 func TestTaskRepository_CreateTask(t *testing.T) {
-	db, cleanup := config.InitTestDBWithDocker()
+	db, cleanup := config.InitTestDB()
 	defer cleanup()
 	repo := NewTaskRepository(db)
 	task := model.Task{
@@ -27,7 +27,7 @@ func TestTaskRepository_CreateTask(t *testing.T) {
 		Status: "status",
 		Date:   time.Now().Truncate(time.Millisecond),
 	}
-	newTask, err := repo.CreateTask(task)
+	newTask, err := repo.CreateTask(t.Context(), task)
 	if err != nil {
 		return
 	}
@@ -36,7 +36,7 @@ func TestTaskRepository_CreateTask(t *testing.T) {
 }
 
 func TestTaskRepository_GetAll(t *testing.T) {
-	db, cleanup := config.InitTestDBWithDocker()
+	db, cleanup := config.InitTestDB()
 	defer cleanup()
 	repo := NewTaskRepository(db)
 	var tasks []model.Task
@@ -54,20 +54,26 @@ func TestTaskRepository_GetAll(t *testing.T) {
 	}
 	tasks = append(tasks, task1, task2)
 
-	_, err := repo.CreateTask(task1)
+	_, err := repo.CreateTask(t.Context(), task1)
 	assert.NoError(t, err)
-	_, err = repo.CreateTask(task2)
+	_, err = repo.CreateTask(t.Context(), task2)
 	assert.NoError(t, err)
 
-	allTasks, err := repo.GetAll()
+	allTasks, err := repo.GetAll(t.Context())
 	if err != nil {
 		return
 	}
 	assert.NotNil(t, allTasks)
-	assert.Equal(t, allTasks, tasks)
+	assert.Len(t, allTasks, len(tasks))
+	for i, task := range tasks {
+		assert.Equal(t, task.Id, allTasks[i].Id)
+		assert.Equal(t, task.Name, allTasks[i].Name)
+		assert.Equal(t, task.Status, allTasks[i].Status)
+		assert.True(t, task.Date.Equal(allTasks[i].Date))
+	}
 }
 func TestTaskRepository_UpdateTaskById(t *testing.T) {
-	db, cleanup := config.InitTestDBWithDocker()
+	db, cleanup := config.InitTestDB()
 	defer cleanup()
 	repo := NewTaskRepository(db)
 	task := model.Task{
@@ -82,13 +88,13 @@ func TestTaskRepository_UpdateTaskById(t *testing.T) {
 		Status: "new_status",
 		Date:   time.Now().Truncate(time.Millisecond),
 	}
-	createTask, err := repo.CreateTask(task)
+	createTask, err := repo.CreateTask(t.Context(), task)
 	assert.NoError(t, err)
 
-	foundTask, err := repo.FindById(task.Id)
+	foundTask, err := repo.FindById(t.Context(), task.Id)
 	assert.NoError(t, err)
 
-	updateTaskById, err := repo.UpdateTaskById(foundTask.Id, updatedTask)
+	updateTaskById, err := repo.UpdateTaskById(t.Context(), foundTask.Id, updatedTask)
 	if err != nil {
 		return
 	}
@@ -99,8 +105,36 @@ func TestTaskRepository_UpdateTaskById(t *testing.T) {
 	assert.Equal(t, updateTaskById.Name, "new_name")
 }
 
+func TestTaskRepository_UpdateTaskByIdVersion(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	created, err := repo.CreateTask(t.Context(), model.Task{Name: "name", Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), created.Version)
+
+	created.Name = "first"
+	updated, err := repo.UpdateTaskById(t.Context(), created.Id, created)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), updated.Version)
+
+	created.Name = "stale"
+	_, err = repo.UpdateTaskById(t.Context(), created.Id, created)
+	assert.ErrorIs(t, err, model.ErrPreconditionFailed)
+
+	created.Version = 0
+	updated, err = repo.UpdateTaskById(t.Context(), created.Id, created)
+	assert.NoError(t, err)
+	assert.Equal(t, "stale", updated.Name)
+	assert.Equal(t, uint(3), updated.Version)
+
+	_, err = repo.UpdateTaskById(t.Context(), 999, model.Task{Name: "missing", Version: 1})
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
+
 func TestTaskRepository_FindById(t *testing.T) {
-	db, cleanup := config.InitTestDBWithDocker()
+	db, cleanup := config.InitTestDB()
 	defer cleanup()
 	repo := NewTaskRepository(db)
 
@@ -110,23 +144,23 @@ func TestTaskRepository_FindById(t *testing.T) {
 		Status: "test_status",
 		Date:   time.Now().Truncate(time.Millisecond),
 	}
-	createdTask, err := repo.CreateTask(task)
+	createdTask, err := repo.CreateTask(t.Context(), task)
 	assert.NoError(t, err)
 	assert.NotNil(t, createdTask)
 
-	foundTask, err := repo.FindById(createdTask.Id)
+	foundTask, err := repo.FindById(t.Context(), createdTask.Id)
 	assert.NoError(t, err)
 	assert.NotNil(t, foundTask)
 	assert.Equal(t, createdTask.Name, foundTask.Name)
 	assert.Equal(t, createdTask.Status, foundTask.Status)
 
-	nonExistentTask, err := repo.FindById(999)
+	nonExistentTask, err := repo.FindById(t.Context(), 999)
 	assert.Error(t, err)
 	assert.Empty(t, nonExistentTask.Id)
 }
 
 func TestTaskRepository_DeleteByID(t *testing.T) {
-	db, cleanup := config.InitTestDBWithDocker()
+	db, cleanup := config.InitTestDB()
 	defer cleanup()
 	repo := NewTaskRepository(db)
 
@@ -136,17 +170,216 @@ func TestTaskRepository_DeleteByID(t *testing.T) {
 		Status: "status_to_delete",
 		Date:   time.Now().Truncate(time.Millisecond),
 	}
-	createdTask, err := repo.CreateTask(task)
+	createdTask, err := repo.CreateTask(t.Context(), task)
 	assert.NoError(t, err)
 	assert.NotNil(t, createdTask)
 
-	err = repo.DeleteByID(createdTask.Id)
+	err = repo.DeleteByID(t.Context(), createdTask.Id, createdTask.Version+1)
+	assert.ErrorIs(t, err, model.ErrPreconditionFailed)
+	err = repo.DeleteByID(t.Context(), createdTask.Id, createdTask.Version)
 	assert.NoError(t, err)
+	err = repo.DeleteByID(t.Context(), createdTask.Id, createdTask.Version)
+	assert.ErrorIs(t, err, model.ErrNotFound)
 
-	deletedTask, err := repo.FindById(createdTask.Id)
+	deletedTask, err := repo.FindById(t.Context(), createdTask.Id)
 	assert.Error(t, err)
 	assert.Empty(t, deletedTask.Id)
 
-	err = repo.DeleteByID(999)
+	err = repo.DeleteByID(t.Context(), 999, 0)
 	assert.Error(t, err, "Ожидается ошибка при удалении несуществующей задачи")
 }
+
+func TestTaskRepository_Find(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	now := time.Now().Truncate(time.Millisecond)
+	for i, name := range []string{"alpha 100%", "beta", "Alpha two"} {
+		_, err := repo.CreateTask(t.Context(), model.Task{
+			Name:   name,
+			Status: "status",
+			Date:   now.Add(time.Duration(i) * time.Minute),
+		})
+		assert.NoError(t, err)
+	}
+
+	page, err := repo.Find(t.Context(), model.TaskQuery{NameContains: "alpha", SortBy: model.SortByDate, SortDesc: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, "Alpha two", page.Tasks[0].Name)
+
+	page, err = repo.Find(t.Context(), model.TaskQuery{NameContains: "0%"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	page, err = repo.Find(t.Context(), model.TaskQuery{SortBy: model.SortByName, Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Len(t, page.Tasks, 1)
+}
+
+func TestTaskRepository_FindWithCursor(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	date := time.Now().Truncate(time.Millisecond)
+	for i := 0; i < 3; i++ {
+		_, err := repo.CreateTask(t.Context(), model.Task{Name: "task", Status: "status", Date: date})
+		assert.NoError(t, err)
+	}
+
+	first, err := repo.Find(t.Context(), model.TaskQuery{SortBy: model.SortByDate, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, first.Tasks, 2)
+	assert.NotNil(t, first.NextCursor)
+
+	second, err := repo.Find(t.Context(), model.TaskQuery{SortBy: model.SortByDate, Limit: 2, After: first.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, second.Tasks, 1)
+	assert.Nil(t, second.NextCursor)
+	assert.Equal(t, int64(3), second.Total)
+	assert.Greater(t, second.Tasks[0].Id, first.Tasks[1].Id)
+}
+
+func TestTaskRepository_UpdateTaskFields(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	createdTask, err := repo.CreateTask(t.Context(), model.Task{Name: "task", Status: model.StatusNew, Priority: model.PriorityLow})
+	assert.NoError(t, err)
+	assert.False(t, createdTask.CreatedAt.IsZero())
+
+	due := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	updated, err := repo.UpdateTaskById(t.Context(), createdTask.Id, model.Task{
+		Name:        "task",
+		Description: "details",
+		Status:      model.StatusInProgress,
+		Priority:    model.PriorityHigh,
+		DueDate:     &due,
+		Assignee:    "alex",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "details", updated.Description)
+	assert.Equal(t, model.PriorityHigh, updated.Priority)
+	assert.Equal(t, "alex", updated.Assignee)
+	assert.True(t, due.Equal(*updated.DueDate))
+	assert.False(t, updated.UpdatedAt.Before(createdTask.UpdatedAt))
+
+	page, err := repo.Find(t.Context(), model.TaskQuery{Priority: model.PriorityHigh, SortBy: model.SortByDueDate})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+}
+
+func TestTaskRepository_FindDueDatesInTimeZones(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	// 10:00 in New York is later than 12:00 in Berlin on the same day
+	berlin := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	newYork := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("EDT", -4*60*60))
+	late, err := repo.CreateTask(t.Context(), model.Task{Name: "late", Status: model.StatusNew, DueDate: &newYork})
+	assert.NoError(t, err)
+	early, err := repo.CreateTask(t.Context(), model.Task{Name: "early", Status: model.StatusNew, DueDate: &berlin})
+	assert.NoError(t, err)
+
+	page, err := repo.Find(t.Context(), model.TaskQuery{SortBy: model.SortByDueDate})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{early.Id, late.Id}, []uint{page.Tasks[0].Id, page.Tasks[1].Id})
+
+	before := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	page, err = repo.Find(t.Context(), model.TaskQuery{DueBefore: &before})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, early.Id, page.Tasks[0].Id)
+}
+
+func TestTaskRepository_FindChildren(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	parent, err := repo.CreateTask(t.Context(), model.Task{Name: "parent", Status: model.StatusNew})
+	assert.NoError(t, err)
+	for _, name := range []string{"first", "second"} {
+		_, err := repo.CreateTask(t.Context(), model.Task{Name: name, Status: model.StatusNew, ParentId: &parent.Id})
+		assert.NoError(t, err)
+	}
+
+	children, err := repo.FindChildren(t.Context(), parent.Id)
+	assert.NoError(t, err)
+	assert.Len(t, children, 2)
+	assert.Equal(t, "first", children[0].Name)
+	assert.Equal(t, parent.Id, *children[1].ParentId)
+}
+
+func TestTaskRepository_Trash(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	parent, err := repo.CreateTask(t.Context(), model.Task{Name: "parent", Status: model.StatusNew})
+	assert.NoError(t, err)
+	child, err := repo.CreateTask(t.Context(), model.Task{Name: "child", Status: model.StatusNew, ParentId: &parent.Id})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.DeleteByID(t.Context(), parent.Id, 0))
+	_, err = repo.FindById(t.Context(), parent.Id)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	all, err := repo.GetAll(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, all, 1)
+	page, err := repo.Find(t.Context(), model.TaskQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	trash, err := repo.FindDeleted(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.True(t, trash[0].DeletedAt.Valid)
+	_, err = repo.FindDeletedById(t.Context(), child.Id)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	touched, err := repo.Touch(t.Context(), parent.Id)
+	assert.NoError(t, err)
+	assert.True(t, touched.DeletedAt.Valid)
+	assert.Equal(t, uint(2), touched.Version)
+	_, err = repo.Touch(t.Context(), child.Id+1)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	restored, err := repo.Restore(t.Context(), parent.Id)
+	assert.NoError(t, err)
+	assert.False(t, restored.DeletedAt.Valid)
+	assert.Equal(t, uint(3), restored.Version)
+	_, err = repo.Restore(t.Context(), parent.Id)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	assert.NoError(t, repo.DeleteByID(t.Context(), parent.Id, 0))
+	assert.ErrorIs(t, repo.Purge(t.Context(), child.Id), model.ErrNotFound)
+	assert.NoError(t, repo.Purge(t.Context(), parent.Id))
+	trash, err = repo.FindDeleted(t.Context())
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+	orphan, err := repo.FindById(t.Context(), child.Id)
+	assert.NoError(t, err)
+	assert.Nil(t, orphan.ParentId)
+}
+
+func TestTaskRepository_CountByStatus(t *testing.T) {
+	db, cleanup := config.InitTestDB()
+	defer cleanup()
+	repo := NewTaskRepository(db)
+
+	for _, status := range []model.Status{model.StatusNew, model.StatusNew, model.StatusDone, model.StatusDone} {
+		_, err := repo.CreateTask(t.Context(), model.Task{Name: "task", Status: status})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, repo.DeleteByID(t.Context(), 4, 0))
+
+	counts, err := repo.CountByStatus(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, map[model.Status]int64{model.StatusNew: 2, model.StatusDone: 1}, counts)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// Repositories bundles the repositories a Transactor binds to a single transaction.
+type Repositories struct {
+	Tasks        TaskRepositoryInterface
+	Tags         TagRepositoryInterface
+	Audit        AuditRepositoryInterface
+	Dependencies DependencyRepositoryInterface
+	Comments     CommentRepositoryInterface
+	Checklists   ChecklistRepositoryInterface
+}
+
+// Transactor runs functions in a transaction of the store behind the repositories.
+type Transactor interface {
+	// InTransaction calls fn with repositories whose changes all belong to one transaction.
+	// The transaction is committed if fn returns nil and rolled back otherwise.
+	InTransaction(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+// GormTransactor implements Transactor using GORM transactions.
+type GormTransactor struct {
+	db       *gorm.DB
+	newTasks func(db *gorm.DB) TaskRepositoryInterface
+}
+
+// NewTransactor creates a new instance of GormTransactor with the specified database connection.
+// newTasks creates the task repository bound to a transaction, such as NewTaskRepository.
+func NewTransactor(db *gorm.DB, newTasks func(db *gorm.DB) TaskRepositoryInterface) Transactor {
+	return &GormTransactor{db: db, newTasks: newTasks}
+}
+
+// InTransaction implements running fn in a database transaction.
+func (t *GormTransactor) InTransaction(ctx context.Context, fn func(repos Repositories) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Tasks:        t.newTasks(tx),
+			Tags:         NewTagRepository(tx),
+			Audit:        NewAuditRepository(tx),
+			Dependencies: NewDependencyRepository(tx),
+			Comments:     NewCommentRepository(tx),
+			Checklists:   NewChecklistRepository(tx),
+		})
+	})
+}
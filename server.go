@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"task_manager_go/config"
+	"task_manager_go/controller"
+	"time"
+)
+
+// workers runs the background goroutines of the server, such as the trash purge, until it shuts down.
+type workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newWorkers creates a new, empty instance of workers.
+func newWorkers() *workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workers{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a goroutine with a context that is cancelled by Stop.
+func (w *workers) Go(fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+	}()
+}
+
+// Stop cancels the context of the goroutines and waits until all of them have returned,
+// so that their last work, such as a final snapshot, is done.
+func (w *workers) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// serve runs server until it fails or the process receives SIGINT or SIGTERM, then drains it:
+// the readiness probe of health fails at once, requests are still accepted for the drain delay,
+// and requests in flight then get the drain timeout to finish before their connections are closed.
+// A second signal during the drain terminates the process at once.
+// Returns the error that stopped the server, nil after a shutdown by signal.
+func serve(server *http.Server, health *controller.HealthController, cfg config.ServerConfig) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.Serve(listener)
+	}()
+	log.Println("Listening on", listener.Addr())
+
+	select {
+	case err := <-failed:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Println("Shutting down, draining requests")
+	health.StartDraining()
+	time.Sleep(cfg.DrainDelay)
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), cfg.DrainTimeout)
+	defer cancel()
+	if err := server.Shutdown(drainCtx); err != nil {
+		log.Println("Requests did not finish in time, closing their connections:", err)
+		server.Close()
+	}
+	if err := <-failed; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+)
+
+// AuditService provides access to the audit trail recorded by TaskService.
+// Uses AuditRepositoryInterface for entry storage.
+type AuditService struct {
+	repo repository.AuditRepositoryInterface
+}
+
+// NewAuditService creates a new instance of AuditService with the specified repository.
+func NewAuditService(repo repository.AuditRepositoryInterface) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// History returns a page of the changes of a task matching the query, most recent first.
+// The history outlives the task, so it is available for deleted and purged tasks as well.
+// Applies the same filters and checks as FindEntries, restricted to the task.
+func (s *AuditService) History(ctx context.Context, taskId uint, query model.AuditQuery) (model.AuditPage, error) {
+	query.TaskId = taskId
+	return s.FindEntries(ctx, query)
+}
+
+// FindEntries returns a page of the audit entries matching the query, most recent first.
+// Applies the same page size limits as FindTasks and rejects negative offsets and empty time ranges.
+func (s *AuditService) FindEntries(ctx context.Context, query model.AuditQuery) (model.AuditPage, error) {
+	if query.Limit < 0 || query.Offset < 0 {
+		return model.AuditPage{}, fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidQuery)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultPageSize
+	}
+	query.Limit = min(query.Limit, MaxPageSize)
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return model.AuditPage{}, fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	}
+	return s.repo.Find(ctx, query)
+}
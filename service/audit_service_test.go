@@ -0,0 +1,98 @@
+package service
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuditedTaskService(opts ...Option) (*TaskService, *AuditService) {
+	taskRepo := repository.NewMockTaskRepository()
+	auditRepo := repository.NewMockAuditRepository()
+	transactor := repository.NewMockTransactor(repository.Repositories{Tasks: taskRepo, Audit: auditRepo})
+	opts = append(opts, WithAudit(auditRepo), WithTransactor(transactor))
+	return NewTaskService(taskRepo, opts...), NewAuditService(auditRepo)
+}
+
+func TestTaskService_AuditTrail(t *testing.T) {
+	taskService, auditService := newAuditedTaskService()
+
+	created, err := taskService.As("alex").CreateTask(t.Context(), model.Task{Name: "Task", Assignee: "alex"})
+	assert.NoError(t, err)
+	_, err = taskService.As("maria").UpdateTask(t.Context(), created.Id, model.Task{Name: "Renamed", Status: model.StatusInProgress})
+	assert.NoError(t, err)
+	assert.NoError(t, taskService.As("maria").DeleteById(t.Context(), created.Id))
+	_, err = taskService.RestoreTask(t.Context(), created.Id)
+	assert.NoError(t, err)
+
+	history, err := auditService.History(t.Context(), created.Id, model.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), history.Total)
+	assert.Equal(t, DefaultPageSize, history.Limit)
+
+	restore, deletion, update, creation := history.Entries[0], history.Entries[1], history.Entries[2], history.Entries[3]
+	assert.Equal(t, model.AuditCreate, creation.Operation)
+	assert.Equal(t, "alex", creation.Actor)
+	assert.Contains(t, creation.Changes, model.FieldChange{Field: "Name", Before: nil, After: "Task"})
+	assert.Contains(t, creation.Changes, model.FieldChange{Field: "Status", Before: nil, After: "New"})
+
+	assert.Equal(t, model.AuditUpdate, update.Operation)
+	assert.Equal(t, "maria", update.Actor)
+	assert.Equal(t, model.FieldChanges{
+		{Field: "Name", Before: "Task", After: "Renamed"},
+		{Field: "Status", Before: "New", After: "InProgress"},
+		{Field: "Assignee", Before: "alex", After: ""},
+	}, update.Changes)
+
+	assert.Equal(t, model.AuditDelete, deletion.Operation)
+	assert.Contains(t, deletion.Changes, model.FieldChange{Field: "Name", Before: "Renamed", After: nil})
+	assert.Equal(t, model.AuditRestore, restore.Operation)
+	assert.Equal(t, DefaultActor, restore.Actor)
+
+	page, err := auditService.FindEntries(t.Context(), model.AuditQuery{Actor: "maria"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	page, err = auditService.FindEntries(t.Context(), model.AuditQuery{From: &update.At, To: &restore.At, Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, []model.AuditEntry{update}, page.Entries)
+
+	_, err = auditService.FindEntries(t.Context(), model.AuditQuery{From: &restore.At, To: &creation.At})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	history, err = auditService.History(t.Context(), created.Id, model.AuditQuery{Actor: "maria"})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.AuditEntry{deletion, update}, history.Entries)
+	_, err = auditService.History(t.Context(), created.Id, model.AuditQuery{Limit: -1})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestTaskService_AuditSideEffects(t *testing.T) {
+	taskService, auditService := newAuditedTaskService()
+
+	parent := createSubtask(t, taskService, "Parent", nil)
+	child := createSubtask(t, taskService, "Child", &parent)
+	assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+
+	history, err := auditService.History(t.Context(), child.Id, model.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.AuditUpdate, history.Entries[0].Operation)
+	assert.Equal(t, model.FieldChanges{{Field: "ParentId", Before: parent.Id, After: nil}}, history.Entries[0].Changes)
+
+	daily, err := taskService.CreateTask(t.Context(), model.Task{Name: "Standup", Recurrence: "FREQ=DAILY"})
+	assert.NoError(t, err)
+	done, err := taskService.UpdateTask(t.Context(), daily.Id, model.Task{Name: "Standup", Status: model.StatusDone, Recurrence: "FREQ=DAILY"})
+	assert.NoError(t, err)
+	history, err = auditService.History(t.Context(), *done.NextOccurrenceId, model.AuditQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.AuditCreate, history.Entries[0].Operation)
+
+	_, err = taskService.PurgeTrash(t.Context(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	history, err = auditService.History(t.Context(), parent.Id, model.AuditQuery{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, model.AuditPurge, history.Entries[0].Operation)
+	assert.Empty(t, history.Entries[0].Changes)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"unicode/utf8"
+)
+
+// MaxChecklistItemLength is the longest checklist item text accepted.
+const MaxChecklistItemLength = 1000
+
+var (
+	// ErrInvalidChecklist is returned when a checklist item has no text or a new order does not fit the checklist.
+	ErrInvalidChecklist = model.NewError(model.ErrValidation, "invalid checklist")
+	// ErrChecklistItemNotFound is returned when a checklist item does not exist on the given task.
+	ErrChecklistItemNotFound = model.NewError(model.ErrNotFound, "checklist item wasn't found")
+)
+
+// ChecklistService provides business logic for the checklists embedded in tasks.
+// Uses ChecklistRepositoryInterface for item storage and TaskRepositoryInterface to check that tasks exist
+// and to increment the version of a task whose checklist changed.
+type ChecklistService struct {
+	repo  repository.ChecklistRepositoryInterface
+	tasks repository.TaskRepositoryInterface
+}
+
+// NewChecklistService creates a new instance of ChecklistService with the specified repositories.
+func NewChecklistService(repo repository.ChecklistRepositoryInterface, tasks repository.TaskRepositoryInterface) *ChecklistService {
+	return &ChecklistService{repo: repo, tasks: tasks}
+}
+
+// GetChecklist returns the checklist of a task with its completion.
+// Returns an error if the task was not found or another error occurred.
+func (s *ChecklistService) GetChecklist(ctx context.Context, taskId uint) (model.Checklist, error) {
+	if _, err := s.tasks.FindById(ctx, taskId); err != nil {
+		return model.Checklist{}, err
+	}
+	items, err := s.repo.FindByTask(ctx, taskId)
+	if err != nil {
+		return model.Checklist{}, err
+	}
+	return model.NewChecklist(items), nil
+}
+
+// AddItem appends an item to the checklist of a task.
+// Returns the created item and an error if the task was not found or the text is invalid.
+func (s *ChecklistService) AddItem(ctx context.Context, taskId uint, text string) (model.ChecklistItem, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return model.ChecklistItem{}, fmt.Errorf("%w: text is required", ErrInvalidChecklist)
+	}
+	if utf8.RuneCountInString(text) > MaxChecklistItemLength {
+		return model.ChecklistItem{}, fmt.Errorf("%w: text is longer than %d characters", ErrInvalidChecklist, MaxChecklistItemLength)
+	}
+	if _, err := s.tasks.FindById(ctx, taskId); err != nil {
+		return model.ChecklistItem{}, err
+	}
+	item, err := s.repo.AddItem(ctx, model.ChecklistItem{TaskId: taskId, Text: text})
+	if err != nil {
+		return model.ChecklistItem{}, err
+	}
+	return item, touchTasks(ctx, s.tasks, taskId)
+}
+
+// ToggleItem marks an unfinished item as done or a done item as unfinished.
+// Returns the updated item and an error if the item was not found on the task or another error occurred.
+func (s *ChecklistService) ToggleItem(ctx context.Context, taskId, itemId uint) (model.ChecklistItem, error) {
+	if err := s.checkItem(ctx, taskId, itemId); err != nil {
+		return model.ChecklistItem{}, err
+	}
+	item, err := s.repo.ToggleItem(ctx, itemId)
+	if err != nil {
+		return model.ChecklistItem{}, err
+	}
+	return item, touchTasks(ctx, s.tasks, taskId)
+}
+
+// Reorder puts the checklist of a task into the order of itemIds, which must list every item exactly once.
+// Returns the reordered checklist and an error if the task was not found or the order does not fit.
+func (s *ChecklistService) Reorder(ctx context.Context, taskId uint, itemIds []uint) (model.Checklist, error) {
+	if _, err := s.tasks.FindById(ctx, taskId); err != nil {
+		return model.Checklist{}, err
+	}
+	items, err := s.repo.Reorder(ctx, taskId, itemIds)
+	if errors.Is(err, repository.ErrChecklistMismatch) {
+		return model.Checklist{}, fmt.Errorf("%w: %w", ErrInvalidChecklist, err)
+	}
+	if err != nil {
+		return model.Checklist{}, err
+	}
+	return model.NewChecklist(items), touchTasks(ctx, s.tasks, taskId)
+}
+
+// DeleteItem removes an item from the checklist of a task.
+// Returns an error if the item was not found on the task or another error occurred.
+func (s *ChecklistService) DeleteItem(ctx context.Context, taskId, itemId uint) error {
+	if err := s.checkItem(ctx, taskId, itemId); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteItem(ctx, itemId); err != nil {
+		return err
+	}
+	return touchTasks(ctx, s.tasks, taskId)
+}
+
+// checkItem makes sure a checklist item belongs to the given task.
+func (s *ChecklistService) checkItem(ctx context.Context, taskId, itemId uint) error {
+	item, err := s.repo.FindById(ctx, itemId)
+	if errors.Is(err, ErrNotFound) || err == nil && item.TaskId != taskId {
+		return ErrChecklistItemNotFound
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"sync"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklistService_Items(t *testing.T) {
+	taskRepo := repository.NewMockTaskRepository()
+	checklistRepo := repository.NewMockChecklistRepository()
+	checklistService := NewChecklistService(checklistRepo, taskRepo)
+	taskService := NewTaskService(taskRepo, WithChecklists(checklistRepo))
+	task, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Release"})
+
+	var ids []uint
+	for _, text := range []string{"Tag", "Build", "Publish", "Announce"} {
+		item, err := checklistService.AddItem(t.Context(), task.Id, text)
+		assert.NoError(t, err)
+		ids = append(ids, item.Id)
+	}
+	assert.Equal(t, []uint{1, 2, 3, 4}, ids)
+
+	_, err := checklistService.ToggleItem(t.Context(), task.Id, ids[0])
+	assert.NoError(t, err)
+	details, err := taskService.GetTaskDetails(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.Len(t, details.Checklist.Items, 4)
+	assert.Equal(t, 25.0, details.Checklist.Completion)
+	assert.Equal(t, uint(6), details.Version)
+
+	checklist, err := checklistService.Reorder(t.Context(), task.Id, []uint{ids[1], ids[0], ids[3], ids[2]})
+	assert.NoError(t, err)
+	assert.Equal(t, "Build", checklist.Items[0].Text)
+	assert.Equal(t, 4, checklist.Items[3].Position)
+
+	_, err = checklistService.Reorder(t.Context(), task.Id, []uint{ids[0], ids[1]})
+	assert.ErrorIs(t, err, ErrInvalidChecklist)
+	_, err = checklistService.Reorder(t.Context(), task.Id, []uint{ids[0], ids[0], ids[1], ids[2]})
+	assert.ErrorIs(t, err, ErrInvalidChecklist)
+
+	assert.NoError(t, checklistService.DeleteItem(t.Context(), task.Id, ids[3]))
+	item, err := checklistService.AddItem(t.Context(), task.Id, "Celebrate")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, item.Position)
+
+	_, err = checklistService.AddItem(t.Context(), task.Id, "  ")
+	assert.ErrorIs(t, err, ErrInvalidChecklist)
+	_, err = checklistService.ToggleItem(t.Context(), task.Id+1, ids[0])
+	assert.ErrorIs(t, err, ErrChecklistItemNotFound)
+
+	assert.NoError(t, taskService.DeleteById(t.Context(), task.Id))
+	items, _ := checklistRepo.FindByTask(t.Context(), task.Id)
+	assert.Len(t, items, 4)
+	_, err = taskService.PurgeTrash(t.Context(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	items, _ = checklistRepo.FindByTask(t.Context(), task.Id)
+	assert.Empty(t, items)
+}
+
+func TestChecklistService_ConcurrentToggles(t *testing.T) {
+	taskRepo := repository.NewMockTaskRepository()
+	checklistService := NewChecklistService(repository.NewMockChecklistRepository(), taskRepo)
+	task, _ := taskRepo.CreateTask(t.Context(), model.Task{Name: "Task", Status: model.StatusNew})
+	item, _ := checklistService.AddItem(t.Context(), task.Id, "Step")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 51; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := checklistService.ToggleItem(t.Context(), task.Id, item.Id)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	checklist, err := checklistService.GetChecklist(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.True(t, checklist.Items[0].Done)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"unicode/utf8"
+)
+
+const (
+	// MaxCommentLength is the longest comment body accepted.
+	MaxCommentLength = 10000
+	// MaxAuthorLength is the longest comment author accepted.
+	MaxAuthorLength = 255
+)
+
+var (
+	// ErrInvalidComment is returned when a comment has no author or body, or they are too long.
+	ErrInvalidComment = model.NewError(model.ErrValidation, "invalid comment")
+	// ErrCommentNotFound is returned when a comment does not exist on the given task.
+	ErrCommentNotFound = model.NewError(model.ErrNotFound, "comment wasn't found")
+)
+
+// CommentService provides business logic for the comment threads of tasks.
+// Uses CommentRepositoryInterface for comment storage and TaskRepositoryInterface to check that tasks exist.
+type CommentService struct {
+	repo  repository.CommentRepositoryInterface
+	tasks repository.TaskRepositoryInterface
+}
+
+// NewCommentService creates a new instance of CommentService with the specified repositories.
+func NewCommentService(repo repository.CommentRepositoryInterface, tasks repository.TaskRepositoryInterface) *CommentService {
+	return &CommentService{repo: repo, tasks: tasks}
+}
+
+// AddComment posts a comment on a task.
+// Returns the created comment and an error if the task was not found or the comment is invalid.
+func (s *CommentService) AddComment(ctx context.Context, taskId uint, author, body string) (model.Comment, error) {
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return model.Comment{}, fmt.Errorf("%w: author is required", ErrInvalidComment)
+	}
+	if utf8.RuneCountInString(author) > MaxAuthorLength {
+		return model.Comment{}, fmt.Errorf("%w: author is longer than %d characters", ErrInvalidComment, MaxAuthorLength)
+	}
+	if err := validateCommentBody(body); err != nil {
+		return model.Comment{}, err
+	}
+	if _, err := s.tasks.FindById(ctx, taskId); err != nil {
+		return model.Comment{}, err
+	}
+	return s.repo.CreateComment(ctx, model.Comment{TaskId: taskId, Author: author, Body: body})
+}
+
+// ListComments returns a page of the comments of a task, oldest first.
+// Applies the same page size limits as FindTasks.
+// Returns an error if the task was not found or another error occurred.
+func (s *CommentService) ListComments(ctx context.Context, taskId uint, limit, offset int) (model.CommentPage, error) {
+	if limit < 0 || offset < 0 {
+		return model.CommentPage{}, fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidQuery)
+	}
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+	if _, err := s.tasks.FindById(ctx, taskId); err != nil {
+		return model.CommentPage{}, err
+	}
+	return s.repo.FindByTask(ctx, taskId, limit, offset)
+}
+
+// EditComment replaces the body of a comment on a task and marks it as edited.
+// Returns the updated comment and an error if the comment was not found on the task or the body is invalid.
+func (s *CommentService) EditComment(ctx context.Context, taskId, commentId uint, body string) (model.Comment, error) {
+	if err := validateCommentBody(body); err != nil {
+		return model.Comment{}, err
+	}
+	if _, err := s.findComment(ctx, taskId, commentId); err != nil {
+		return model.Comment{}, err
+	}
+	return s.repo.UpdateComment(ctx, commentId, body)
+}
+
+// DeleteComment deletes a comment on a task.
+// Returns an error if the comment was not found on the task or another error occurred.
+func (s *CommentService) DeleteComment(ctx context.Context, taskId, commentId uint) error {
+	if _, err := s.findComment(ctx, taskId, commentId); err != nil {
+		return err
+	}
+	return s.repo.DeleteComment(ctx, commentId)
+}
+
+// findComment returns a comment, making sure it belongs to the given task.
+func (s *CommentService) findComment(ctx context.Context, taskId, commentId uint) (model.Comment, error) {
+	comment, err := s.repo.FindById(ctx, commentId)
+	if errors.Is(err, ErrNotFound) || err == nil && comment.TaskId != taskId {
+		return model.Comment{}, ErrCommentNotFound
+	}
+	return comment, err
+}
+
+// validateCommentBody checks that a comment body is present and not too long.
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("%w: body is required", ErrInvalidComment)
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return fmt.Errorf("%w: body is longer than %d characters", ErrInvalidComment, MaxCommentLength)
+	}
+	return nil
+}
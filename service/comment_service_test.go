@@ -0,0 +1,76 @@
+package service
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentService_Thread(t *testing.T) {
+	taskRepo := repository.NewMockTaskRepository()
+	commentService := NewCommentService(repository.NewMockCommentRepository(), taskRepo)
+	task, _ := taskRepo.CreateTask(t.Context(), model.Task{Name: "Task", Status: model.StatusNew})
+	other, _ := taskRepo.CreateTask(t.Context(), model.Task{Name: "Other", Status: model.StatusNew})
+
+	first, err := commentService.AddComment(t.Context(), task.Id, "alex", "First!")
+	assert.NoError(t, err)
+	assert.False(t, first.Edited)
+	for _, body := range []string{"second", "third"} {
+		_, err := commentService.AddComment(t.Context(), task.Id, "kim", body)
+		assert.NoError(t, err)
+	}
+	_, err = commentService.AddComment(t.Context(), other.Id, "kim", "elsewhere")
+	assert.NoError(t, err)
+
+	page, err := commentService.ListComments(t.Context(), task.Id, 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Len(t, page.Comments, 2)
+	assert.Equal(t, "second", page.Comments[0].Body)
+
+	edited, err := commentService.EditComment(t.Context(), task.Id, first.Id, "First, edited")
+	assert.NoError(t, err)
+	assert.True(t, edited.Edited)
+	assert.Equal(t, "First, edited", edited.Body)
+
+	_, err = commentService.EditComment(t.Context(), other.Id, first.Id, "hijack")
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+	assert.ErrorIs(t, commentService.DeleteComment(t.Context(), other.Id, first.Id), ErrCommentNotFound)
+	assert.NoError(t, commentService.DeleteComment(t.Context(), task.Id, first.Id))
+
+	_, err = commentService.AddComment(t.Context(), task.Id, " ", "anonymous")
+	assert.ErrorIs(t, err, ErrInvalidComment)
+	_, err = commentService.AddComment(t.Context(), task.Id, "alex", "")
+	assert.ErrorIs(t, err, ErrInvalidComment)
+	_, err = commentService.AddComment(t.Context(), 999, "alex", "nowhere")
+	assert.Error(t, err)
+}
+
+func TestTaskService_PurgeRemovesComments(t *testing.T) {
+	taskRepo := repository.NewMockTaskRepository()
+	commentRepo := repository.NewMockCommentRepository()
+	taskService := NewTaskService(taskRepo, WithComments(commentRepo), WithChildDeletePolicy(DeleteCascade))
+	commentService := NewCommentService(commentRepo, taskRepo)
+
+	parent, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Parent"})
+	child, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Child", ParentId: &parent.Id})
+	commentService.AddComment(t.Context(), parent.Id, "alex", "on the parent")
+	commentService.AddComment(t.Context(), child.Id, "alex", "on the child")
+
+	assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+	page, err := commentRepo.FindByTask(t.Context(), child.Id, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	purged, err := taskService.PurgeTrash(t.Context(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	for _, id := range []uint{parent.Id, child.Id} {
+		page, err := commentRepo.FindByTask(t.Context(), id, 0, 0)
+		assert.NoError(t, err)
+		assert.Zero(t, page.Total)
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+import "task_manager_go/model"
+
+// Kinds of the errors returned by the services, see model.DomainError.
+// Every error a client can act on wraps one of them, so callers can map them without knowing every sentinel.
+var (
+	// ErrNotFound is the kind of errors reporting that a task or another entity does not exist.
+	ErrNotFound = model.ErrNotFound
+	// ErrValidation is the kind of errors reporting invalid input.
+	ErrValidation = model.ErrValidation
+	// ErrConflict is the kind of errors reporting that a change clashes with the current state.
+	ErrConflict = model.ErrConflict
+	// ErrPreconditionFailed is the kind of errors reporting that a change was based on an outdated version.
+	ErrPreconditionFailed = model.ErrPreconditionFailed
+)
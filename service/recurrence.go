@@ -0,0 +1,243 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"task_manager_go/model"
+	"time"
+)
+
+// ErrInvalidRecurrence is returned when a recurrence rule or its time zone cannot be used.
+var ErrInvalidRecurrence = model.NewError(model.ErrValidation, "invalid recurrence")
+
+// Frequency is the basic period of a recurrence rule.
+type Frequency string
+
+// Frequencies supported by recurrence rules.
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// maxPeriods bounds the search for the next occurrence so that rules that can never match,
+// such as the 31st of every second February, do not loop forever.
+const maxPeriods = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// RecurrenceRule is the subset of an RFC 5545 RRULE supported for recurring tasks:
+// FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL, BYDAY without ordinals, BYMONTHDAY, COUNT and UNTIL.
+// Weeks start on Monday.
+type RecurrenceRule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// ParseRecurrenceRule parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+// An optional "RRULE:" prefix is accepted. A date-only UNTIL is read in loc and includes the whole day.
+func ParseRecurrenceRule(value string, loc *time.Location) (RecurrenceRule, error) {
+	rule := RecurrenceRule{Interval: 1}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return RecurrenceRule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrence, part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				err = fmt.Errorf("unsupported frequency %q", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(val)
+		case "COUNT":
+			rule.Count, err = parsePositive(val)
+		case "UNTIL":
+			rule.Until, err = parseUntil(val, loc)
+		case "BYDAY":
+			rule.ByDay, err = parseWeekdays(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseMonthDays(val)
+		case "WKST":
+			if strings.ToUpper(val) != "MO" {
+				err = fmt.Errorf("only weeks starting on Monday are supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %q", key)
+		}
+		if err != nil {
+			return RecurrenceRule{}, fmt.Errorf("%w: %w", ErrInvalidRecurrence, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return RecurrenceRule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRecurrence)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return RecurrenceRule{}, fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRecurrence)
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return RecurrenceRule{}, fmt.Errorf("%w: BYMONTHDAY requires FREQ=MONTHLY", ErrInvalidRecurrence)
+	}
+	if len(rule.ByMonthDay) > 0 && len(rule.ByDay) > 0 {
+		return RecurrenceRule{}, fmt.Errorf("%w: BYDAY and BYMONTHDAY cannot be combined", ErrInvalidRecurrence)
+	}
+	return rule, nil
+}
+
+// Next returns the first occurrence strictly after the given occurrence, or false when the rule ends before.
+// The occurrence fixes the phase of the series and the wall-clock time of day in loc,
+// so a task at 09:00 stays at 09:00 local time across daylight saving changes.
+// COUNT is not applied here because it depends on the number of the occurrence in its series.
+func (r RecurrenceRule) Next(occurrence time.Time, loc *time.Location) (time.Time, bool) {
+	occurrence = occurrence.In(loc)
+	for period := 0; period < maxPeriods; period++ {
+		for _, candidate := range r.candidates(occurrence, period*r.Interval, loc) {
+			if !candidate.After(occurrence) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Occurrences returns up to n occurrences following the given occurrence.
+// number is the position of the given occurrence in its series, starting at 1, and is used to honor COUNT.
+func (r RecurrenceRule) Occurrences(occurrence time.Time, number, n int, loc *time.Location) []time.Time {
+	result := make([]time.Time, 0, n)
+	for len(result) < n && (r.Count == 0 || number < r.Count) {
+		next, ok := r.Next(occurrence, loc)
+		if !ok {
+			break
+		}
+		result = append(result, next)
+		occurrence = next
+		number++
+	}
+	return result
+}
+
+// candidates returns the occurrences of the period that lies offset periods after the period of base, in order.
+func (r RecurrenceRule) candidates(base time.Time, offset int, loc *time.Location) []time.Time {
+	year, month, day := base.Date()
+	hour, minute, second := base.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, second, 0, loc)
+	}
+
+	switch r.Freq {
+	case Daily:
+		candidate := at(year, month, day+offset)
+		if len(r.ByDay) > 0 && !slices.Contains(r.ByDay, candidate.Weekday()) {
+			return nil
+		}
+		return []time.Time{candidate}
+
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{base.Weekday()}
+		}
+		monday := day - daysSinceMonday(base.Weekday()) + offset*7
+		var result []time.Time
+		for _, weekday := range days {
+			result = append(result, at(year, month, monday+daysSinceMonday(weekday)))
+		}
+		slices.SortFunc(result, time.Time.Compare)
+		return result
+
+	default:
+		first := time.Date(year, month+time.Month(offset), 1, 0, 0, 0, 0, loc)
+		length := time.Date(first.Year(), first.Month()+1, 0, 0, 0, 0, 0, loc).Day()
+		var result []time.Time
+		for d := 1; d <= length; d++ {
+			if r.matchesMonthDay(first.AddDate(0, 0, d-1).Weekday(), d, length, day) {
+				result = append(result, at(first.Year(), first.Month(), d))
+			}
+		}
+		return result
+	}
+}
+
+// matchesMonthDay reports whether day d of a month with the given length is an occurrence of a monthly rule.
+// Without BYDAY or BYMONTHDAY the rule repeats on the day of month of the series, skipping shorter months.
+func (r RecurrenceRule) matchesMonthDay(weekday time.Weekday, d, length, seriesDay int) bool {
+	switch {
+	case len(r.ByDay) > 0:
+		return slices.Contains(r.ByDay, weekday)
+	case len(r.ByMonthDay) > 0:
+		return slices.Contains(r.ByMonthDay, d) || slices.Contains(r.ByMonthDay, d-length-1)
+	default:
+		return d == seriesDay
+	}
+}
+
+// daysSinceMonday returns how many days the weekday lies after Monday.
+func daysSinceMonday(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive number", value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string, loc *time.Location) (*time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return &until, nil
+	}
+	if until, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return &until, nil
+	}
+	day, err := time.ParseInLocation("20060102", value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("UNTIL %q is not a date or date-time", value)
+	}
+	until := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return &until, nil
+}
+
+func parseWeekdays(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		weekday, ok := weekdays[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported weekday %q", name)
+		}
+		if !slices.Contains(days, weekday) {
+			days = append(days, weekday)
+		}
+	}
+	return days, nil
+}
+
+func parseMonthDays(value string) ([]int, error) {
+	var days []int
+	for _, part := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(part)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("invalid day of month %q", part)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
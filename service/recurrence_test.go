@@ -0,0 +1,127 @@
+package service
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	rule, err := ParseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, Weekly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []time.Weekday{time.Monday, time.Wednesday}, rule.ByDay)
+	assert.Equal(t, 4, rule.Count)
+
+	rule, err = ParseRecurrenceRule("FREQ=DAILY;UNTIL=20250110", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 10, 23, 59, 59, 999999999, time.UTC), *rule.Until)
+
+	for _, value := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		_, err := ParseRecurrenceRule(value, time.UTC)
+		assert.ErrorIs(t, err, ErrInvalidRecurrence, value)
+	}
+}
+
+func TestRecurrenceRule_Occurrences(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC) // Monday
+
+	rule, _ := ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", time.UTC)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 22, 9, 0, 0, 0, time.UTC),
+	}, rule.Occurrences(start, 1, 3, time.UTC))
+
+	rule, _ = ParseRecurrenceRule("FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=3", time.UTC)
+	friday := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC),
+	}, rule.Occurrences(friday, 2, 10, time.UTC))
+
+	rule, _ = ParseRecurrenceRule("FREQ=MONTHLY", time.UTC)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 5, 31, 9, 0, 0, 0, time.UTC),
+	}, rule.Occurrences(time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), 1, 2, time.UTC))
+
+	rule, _ = ParseRecurrenceRule("FREQ=MONTHLY;BYMONTHDAY=-1;UNTIL=20250331", time.UTC)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 31, 9, 0, 0, 0, time.UTC),
+	}, rule.Occurrences(time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC), 1, 5, time.UTC))
+}
+
+func TestRecurrenceRule_DaylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	rule, _ := ParseRecurrenceRule("FREQ=DAILY", berlin)
+
+	// Daylight saving time starts in Berlin on 30 March 2025
+	occurrences := rule.Occurrences(time.Date(2025, 3, 29, 9, 0, 0, 0, berlin), 1, 2, berlin)
+	assert.Len(t, occurrences, 2)
+	for _, occurrence := range occurrences {
+		assert.Equal(t, 9, occurrence.Hour())
+	}
+	assert.Equal(t, 23*time.Hour, occurrences[0].Sub(time.Date(2025, 3, 29, 9, 0, 0, 0, berlin)))
+}
+
+func TestTaskService_CompleteRecurringTask(t *testing.T) {
+	mockRepo := repository.NewMockTaskRepository()
+	taskService := NewTaskService(mockRepo)
+
+	due := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC) // Monday
+	task, err := taskService.CreateTask(t.Context(), model.Task{
+		Name:       "Standup",
+		DueDate:    &due,
+		Recurrence: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=2",
+		Occurrence: 7,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, task.Occurrence)
+
+	preview, err := taskService.PreviewOccurrences(t.Context(), task.Id, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2030, 1, 9, 9, 0, 0, 0, time.UTC)}, preview)
+
+	done, err := taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Standup", DueDate: &due, Recurrence: task.Recurrence, Status: model.StatusDone})
+	assert.NoError(t, err)
+	assert.NotNil(t, done.NextOccurrenceId)
+
+	next, err := taskService.GetTaskByID(t.Context(), *done.NextOccurrenceId)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusNew, next.Status)
+	assert.Equal(t, 2, next.Occurrence)
+	assert.Equal(t, preview[0], *next.DueDate)
+
+	// reopening and completing again does not create a second occurrence
+	_, err = taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Standup", DueDate: &due, Recurrence: task.Recurrence, Status: model.StatusInProgress})
+	assert.NoError(t, err)
+	_, err = taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Standup", DueDate: &due, Recurrence: task.Recurrence, Status: model.StatusDone})
+	assert.NoError(t, err)
+	tasks, _ := taskService.GetAllTasks(t.Context())
+	assert.Len(t, tasks, 2)
+
+	// the series ends after COUNT occurrences
+	last, err := taskService.UpdateTask(t.Context(), next.Id, model.Task{Name: "Standup", DueDate: next.DueDate, Recurrence: next.Recurrence, Status: model.StatusDone})
+	assert.NoError(t, err)
+	assert.Nil(t, last.NextOccurrenceId)
+
+	_, err = taskService.CreateTask(t.Context(), model.Task{Name: "Task", Recurrence: "FREQ=DAILY", TimeZone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, ErrInvalidRecurrence)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"unicode/utf8"
+)
+
+// MaxTagNameLength is the longest tag name accepted.
+const MaxTagNameLength = 64
+
+var (
+	// ErrInvalidTag is returned when a tag name is empty, too long or otherwise unusable.
+	ErrInvalidTag = model.NewError(model.ErrValidation, "invalid tag")
+	// ErrTagExists is returned when a tag is created or renamed with a name that is already taken.
+	ErrTagExists = model.NewError(model.ErrConflict, "tag already exists")
+)
+
+// TagService provides business logic for tags and their attachment to tasks.
+// Uses TagRepositoryInterface for tag storage and TaskRepositoryInterface to check that tasks exist
+// and to increment the versions of the tasks whose tags changed.
+type TagService struct {
+	repo       repository.TagRepositoryInterface
+	tasks      repository.TaskRepositoryInterface
+	transactor repository.Transactor
+}
+
+// NewTagService creates a new instance of TagService with the specified repositories.
+// transactor, if not nil, runs every change of tags in a transaction, so that a tag and the versions
+// of the tasks carrying it change together or not at all.
+func NewTagService(repo repository.TagRepositoryInterface, tasks repository.TaskRepositoryInterface, transactor repository.Transactor) *TagService {
+	return &TagService{repo: repo, tasks: tasks, transactor: transactor}
+}
+
+// inTransaction runs fn with a TagService whose repositories belong to one transaction of the transactor,
+// or with s itself when there is no transactor.
+func (s *TagService) inTransaction(ctx context.Context, fn func(tx *TagService) error) error {
+	if s.transactor == nil {
+		return fn(s)
+	}
+	return s.transactor.InTransaction(ctx, func(repos repository.Repositories) error {
+		return fn(&TagService{repo: repos.Tags, tasks: repos.Tasks})
+	})
+}
+
+// CreateTag creates a new tag with the given name.
+// Returns the created tag and an error if the name is invalid or already taken.
+func (s *TagService) CreateTag(ctx context.Context, name string) (model.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	_, err = s.repo.FindByName(ctx, name)
+	if err == nil {
+		return model.Tag{}, fmt.Errorf("%w: %q", ErrTagExists, name)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return model.Tag{}, err
+	}
+	return s.repo.CreateTag(ctx, model.Tag{Name: name})
+}
+
+// GetAllTags returns all tags ordered by name.
+func (s *TagService) GetAllTags(ctx context.Context) ([]model.Tag, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// GetTag finds a tag by its ID.
+func (s *TagService) GetTag(ctx context.Context, id uint) (model.Tag, error) {
+	return s.repo.FindById(ctx, id)
+}
+
+// RenameTag gives a tag a new name, which every task carrying it picks up.
+// Returns the renamed tag and an error if the tag was not found or the name is invalid or taken.
+func (s *TagService) RenameTag(ctx context.Context, id uint, name string) (renamed model.Tag, err error) {
+	err = s.inTransaction(ctx, func(tx *TagService) error {
+		renamed, err = tx.renameTag(ctx, id, name)
+		return err
+	})
+	return renamed, err
+}
+
+// renameTag renames a tag and increments the versions of the tasks carrying it.
+func (s *TagService) renameTag(ctx context.Context, id uint, name string) (model.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	tag, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	existing, err := s.repo.FindByName(ctx, name)
+	if err == nil && existing.Id != id {
+		return model.Tag{}, fmt.Errorf("%w: %q, merge the tags instead", ErrTagExists, name)
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return model.Tag{}, err
+	}
+	tagged, err := s.taggedTasks(ctx, tag)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	renamed, err := s.repo.RenameTag(ctx, id, name)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	return renamed, touchTasks(ctx, s.tasks, tagged...)
+}
+
+// MergeTags replaces the source tag with the target tag on every task and deletes the source tag.
+// Returns the target tag and an error if either tag was not found or they are the same tag.
+func (s *TagService) MergeTags(ctx context.Context, sourceId, targetId uint) (target model.Tag, err error) {
+	err = s.inTransaction(ctx, func(tx *TagService) error {
+		target, err = tx.mergeTags(ctx, sourceId, targetId)
+		return err
+	})
+	return target, err
+}
+
+// mergeTags merges two tags and increments the versions of the tasks carrying the source tag.
+func (s *TagService) mergeTags(ctx context.Context, sourceId, targetId uint) (model.Tag, error) {
+	if sourceId == targetId {
+		return model.Tag{}, fmt.Errorf("%w: a tag cannot be merged into itself", ErrInvalidTag)
+	}
+	source, err := s.repo.FindById(ctx, sourceId)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	target, err := s.repo.FindById(ctx, targetId)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	tagged, err := s.taggedTasks(ctx, source)
+	if err != nil {
+		return model.Tag{}, err
+	}
+	if err := s.repo.MergeTags(ctx, sourceId, targetId); err != nil {
+		return model.Tag{}, err
+	}
+	return target, touchTasks(ctx, s.tasks, tagged...)
+}
+
+// DeleteTag deletes a tag and detaches it from every task.
+// Returns an error if the tag was not found or another error occurred.
+func (s *TagService) DeleteTag(ctx context.Context, id uint) error {
+	return s.inTransaction(ctx, func(tx *TagService) error {
+		return tx.deleteTag(ctx, id)
+	})
+}
+
+// deleteTag deletes a tag and increments the versions of the tasks carrying it.
+func (s *TagService) deleteTag(ctx context.Context, id uint) error {
+	tag, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	tagged, err := s.taggedTasks(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTag(ctx, id); err != nil {
+		return err
+	}
+	return touchTasks(ctx, s.tasks, tagged...)
+}
+
+// taggedTasks returns the ids of the tasks carrying a tag, whose versions change with the tag.
+// Tasks in the trash are included, so that they are restored with the version of their current tags.
+func (s *TagService) taggedTasks(ctx context.Context, tag model.Tag) ([]uint, error) {
+	page, err := s.tasks.Find(ctx, model.TaskQuery{Tags: []string{tag.Name}, SortBy: model.SortById})
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.tasks.FindDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(page.Tasks))
+	for _, task := range page.Tasks {
+		ids = append(ids, task.Id)
+	}
+	for _, task := range deleted {
+		if slices.ContainsFunc(task.Tags, func(t model.Tag) bool { return t.Id == tag.Id }) {
+			ids = append(ids, task.Id)
+		}
+	}
+	return ids, nil
+}
+
+// GetTaskTags returns the tags attached to a task.
+// Returns an error if the task was not found or another error occurred.
+func (s *TagService) GetTaskTags(ctx context.Context, taskId uint) ([]model.Tag, error) {
+	if _, err := s.tasks.FindById(ctx, taskId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByTask(ctx, taskId)
+}
+
+// AttachTags attaches the named tags to a task, creating tags that do not exist yet.
+// Returns all tags of the task and an error if the task was not found or a name is invalid.
+func (s *TagService) AttachTags(ctx context.Context, taskId uint, names []string) (tags []model.Tag, err error) {
+	err = s.inTransaction(ctx, func(tx *TagService) error {
+		tags, err = tx.attachTags(ctx, taskId, names)
+		return err
+	})
+	return tags, err
+}
+
+// attachTags attaches the named tags to a task and increments its version.
+func (s *TagService) attachTags(ctx context.Context, taskId uint, names []string) ([]model.Tag, error) {
+	if _, err := s.tasks.FindById(ctx, taskId); err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		name, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := s.repo.FindByName(ctx, name)
+		if errors.Is(err, ErrNotFound) {
+			tag, err = s.repo.CreateTag(ctx, model.Tag{Name: name})
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, tag.Id)
+	}
+	if err := s.repo.AttachTags(ctx, taskId, ids); err != nil {
+		return nil, err
+	}
+	if err := touchTasks(ctx, s.tasks, taskId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByTask(ctx, taskId)
+}
+
+// DetachTag detaches a tag from a task.
+// Returns an error if the task was not found or another error occurred.
+func (s *TagService) DetachTag(ctx context.Context, taskId, tagId uint) error {
+	return s.inTransaction(ctx, func(tx *TagService) error {
+		if _, err := tx.tasks.FindById(ctx, taskId); err != nil {
+			return err
+		}
+		if err := tx.repo.DetachTag(ctx, taskId, tagId); err != nil {
+			return err
+		}
+		return touchTasks(ctx, tx.tasks, taskId)
+	})
+}
+
+// normalizeTagName trims a tag name and checks that it is usable.
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidTag)
+	}
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		return "", fmt.Errorf("%w: name is longer than %d characters", ErrInvalidTag, MaxTagNameLength)
+	}
+	if strings.ContainsRune(name, ',') {
+		return "", fmt.Errorf("%w: name cannot contain commas", ErrInvalidTag)
+	}
+	return name, nil
+}
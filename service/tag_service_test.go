@@ -0,0 +1,124 @@
+package service
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tagNames(tags []model.Tag) []string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+func TestTagService_AttachAndFilter(t *testing.T) {
+	taskRepo := repository.NewMockTaskRepository()
+	taskService := NewTaskService(taskRepo)
+	tagService := NewTagService(repository.NewMockTagRepository(taskRepo.MemoryTaskRepository), taskRepo, nil)
+
+	api, _ := taskService.CreateTask(t.Context(), model.Task{Name: "API"})
+	ui, _ := taskService.CreateTask(t.Context(), model.Task{Name: "UI"})
+	taskService.CreateTask(t.Context(), model.Task{Name: "Untagged"})
+
+	tags, err := tagService.AttachTags(t.Context(), api.Id, []string{"backend", " team-a ", "backend"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backend", "team-a"}, tagNames(tags))
+	_, err = tagService.AttachTags(t.Context(), ui.Id, []string{"frontend", "team-a"})
+	assert.NoError(t, err)
+
+	page, err := taskService.FindTasks(t.Context(), model.TaskQuery{Tags: []string{"backend", "frontend"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+
+	page, err = taskService.FindTasks(t.Context(), model.TaskQuery{Tags: []string{"backend", "team-a"}, MatchAllTags: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, "API", page.Tasks[0].Name)
+
+	assert.NoError(t, tagService.DetachTag(t.Context(), api.Id, tags[0].Id))
+	tags, err = tagService.GetTaskTags(t.Context(), api.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, tagNames(tags))
+
+	_, err = tagService.AttachTags(t.Context(), api.Id, []string{""})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	_, err = tagService.AttachTags(t.Context(), 999, []string{"backend"})
+	assert.Error(t, err)
+}
+
+func TestTagService_RenameAndMerge(t *testing.T) {
+	taskRepo := repository.NewMockTaskRepository()
+	taskService := NewTaskService(taskRepo)
+	tagService := NewTagService(repository.NewMockTagRepository(taskRepo.MemoryTaskRepository), taskRepo, nil)
+
+	first, _ := taskService.CreateTask(t.Context(), model.Task{Name: "First"})
+	second, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Second"})
+	tagService.AttachTags(t.Context(), first.Id, []string{"ui", "front-end"})
+	tagService.AttachTags(t.Context(), second.Id, []string{"frontend"})
+
+	ui, _ := tagService.repo.FindByName(t.Context(), "ui")
+	renamed, err := tagService.RenameTag(t.Context(), ui.Id, "design")
+	assert.NoError(t, err)
+	assert.Equal(t, "design", renamed.Name)
+	task, _ := taskService.GetTaskByID(t.Context(), first.Id)
+	assert.Equal(t, []string{"design", "front-end"}, tagNames(task.Tags))
+	assert.Equal(t, uint(3), task.Version)
+
+	_, err = tagService.RenameTag(t.Context(), ui.Id, "frontend")
+	assert.ErrorIs(t, err, ErrTagExists)
+	_, err = tagService.CreateTag(t.Context(), "frontend")
+	assert.ErrorIs(t, err, ErrTagExists)
+
+	source, _ := tagService.repo.FindByName(t.Context(), "front-end")
+	target, _ := tagService.repo.FindByName(t.Context(), "frontend")
+	_, err = tagService.MergeTags(t.Context(), source.Id, target.Id)
+	assert.NoError(t, err)
+	_, err = tagService.MergeTags(t.Context(), target.Id, target.Id)
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	page, err := taskService.FindTasks(t.Context(), model.TaskQuery{Tags: []string{"frontend"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	tags, _ := tagService.GetAllTags(t.Context())
+	assert.Equal(t, []string{"design", "frontend"}, tagNames(tags))
+
+	task, _ = taskService.GetTaskByID(t.Context(), first.Id)
+	assert.Equal(t, uint(4), task.Version)
+	task, _ = taskService.GetTaskByID(t.Context(), second.Id)
+	assert.Equal(t, uint(2), task.Version)
+
+	assert.NoError(t, tagService.DeleteTag(t.Context(), target.Id))
+	task, _ = taskService.GetTaskByID(t.Context(), second.Id)
+	assert.Empty(t, task.Tags)
+	assert.Equal(t, uint(3), task.Version)
+	assert.ErrorIs(t, tagService.DeleteTag(t.Context(), target.Id), ErrNotFound)
+}
+
+func TestTagService_TrashedTasks(t *testing.T) {
+	store := repository.NewMemoryStore(repository.NewMemoryTaskRepository())
+	taskService := NewTaskService(store.Tasks, WithTransactor(store))
+	tagService := NewTagService(store.Tags, store.Tasks, store)
+
+	task, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Trashed"})
+	tags, err := tagService.AttachTags(t.Context(), task.Id, []string{"ui"})
+	assert.NoError(t, err)
+	assert.NoError(t, taskService.DeleteById(t.Context(), task.Id))
+	trashed, _ := store.Tasks.FindDeletedById(t.Context(), task.Id)
+
+	_, err = tagService.RenameTag(t.Context(), tags[0].Id, "design")
+	assert.NoError(t, err)
+	touched, _ := store.Tasks.FindDeletedById(t.Context(), task.Id)
+	assert.Equal(t, trashed.Version+1, touched.Version)
+	assert.Equal(t, []string{"design"}, tagNames(touched.Tags))
+
+	assert.NoError(t, tagService.DeleteTag(t.Context(), tags[0].Id))
+	restored, err := taskService.RestoreTask(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, restored.Tags)
+	assert.Equal(t, trashed.Version+3, restored.Version)
+}
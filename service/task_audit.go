@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"time"
+)
+
+// DefaultActor is the actor recorded for changes made by a TaskService that was not bound to an actor with As,
+// such as the trash purge.
+const DefaultActor = "system"
+
+// As returns a copy of the service that records its changes in the audit trail as made by actor.
+func (t *TaskService) As(actor string) *TaskService {
+	bound := *t
+	bound.actor = actor
+	return &bound
+}
+
+// inTransaction calls fn with a copy of the service whose repositories share one transaction of the transactor.
+// Without a transactor fn is called with the service itself.
+// Repositories the service does not use stay unused inside the transaction.
+func (t *TaskService) inTransaction(ctx context.Context, fn func(tx *TaskService) error) error {
+	if t.transactor == nil {
+		return fn(t)
+	}
+	return t.transactor.InTransaction(ctx, func(repos repository.Repositories) error {
+		tx := *t
+		// changes made inside fn already belong to this transaction
+		tx.transactor = nil
+		tx.repo = repos.Tasks
+		if t.audit != nil {
+			tx.audit = repos.Audit
+		}
+		if t.deps != nil {
+			tx.deps = repos.Dependencies
+		}
+		if t.comments != nil {
+			tx.comments = repos.Comments
+		}
+		if t.checklists != nil {
+			tx.checklists = repos.Checklists
+		}
+		return fn(&tx)
+	})
+}
+
+// record adds an entry for a change of a task to the audit trail, if the service has one.
+// before is nil for a created task and after is nil for a deleted one.
+func (t *TaskService) record(ctx context.Context, operation model.AuditOperation, taskId uint, before, after *model.Task) error {
+	if t.audit == nil {
+		return nil
+	}
+	_, err := t.audit.Record(ctx, model.AuditEntry{
+		TaskId:    taskId,
+		Actor:     t.actor,
+		Operation: operation,
+		Changes:   model.DiffTasks(before, after),
+		At:        time.Now(),
+	})
+	return err
+}
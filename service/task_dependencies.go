@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"task_manager_go/model"
+)
+
+var (
+	// ErrDependenciesDisabled is returned by dependency operations of a service without a dependency repository.
+	ErrDependenciesDisabled = model.NewError(errors.ErrUnsupported, "task dependencies are not enabled")
+	// ErrDependencyCycle is returned when a new dependency would make a task transitively depend on itself.
+	ErrDependencyCycle = model.NewError(model.ErrConflict, "dependency would create a cycle")
+	// ErrBlocked is returned when a task is started or finished while some of its blockers are still open.
+	ErrBlocked = model.NewError(model.ErrConflict, "task is blocked by open tasks")
+)
+
+// AddDependency records that a task cannot start until the blocker is done.
+// Returns an error if either task was not found, the dependency would create a cycle or another error occurred.
+func (t *TaskService) AddDependency(ctx context.Context, taskId, blockerId uint) (dependency model.TaskDependency, err error) {
+	defer t.observe("add_dependency", &err)
+	if t.deps == nil {
+		return model.TaskDependency{}, ErrDependenciesDisabled
+	}
+	if _, err := t.repo.FindById(ctx, taskId); err != nil {
+		return model.TaskDependency{}, err
+	}
+	if _, err := t.repo.FindById(ctx, blockerId); err != nil {
+		return model.TaskDependency{}, err
+	}
+	reachable, err := t.dependsOn(ctx, blockerId, taskId)
+	if err != nil {
+		return model.TaskDependency{}, err
+	}
+	if taskId == blockerId || reachable {
+		return model.TaskDependency{}, fmt.Errorf("%w: task %d already depends on task %d", ErrDependencyCycle, blockerId, taskId)
+	}
+	return t.deps.AddDependency(ctx, model.TaskDependency{TaskId: taskId, BlockerId: blockerId})
+}
+
+// RemoveDependency removes the dependency of a task on a blocker.
+// Returns an error if the dependency was not found or another error occurred.
+func (t *TaskService) RemoveDependency(ctx context.Context, taskId, blockerId uint) (err error) {
+	defer t.observe("remove_dependency", &err)
+	if t.deps == nil {
+		return ErrDependenciesDisabled
+	}
+	return t.deps.RemoveDependency(ctx, taskId, blockerId)
+}
+
+// GetDependencyGraph returns a task with every task it transitively depends on,
+// the dependencies between them and an order in which they can be worked on.
+// Blockers in the trash are left out with their dependencies until they are restored.
+// Returns an error if the task was not found or another error occurred.
+func (t *TaskService) GetDependencyGraph(ctx context.Context, taskId uint) (graph model.DependencyGraph, err error) {
+	defer t.observe("get_dependency_graph", &err)
+	if t.deps == nil {
+		return model.DependencyGraph{}, ErrDependenciesDisabled
+	}
+	task, err := t.repo.FindById(ctx, taskId)
+	if err != nil {
+		return model.DependencyGraph{}, err
+	}
+
+	graph = model.DependencyGraph{
+		TaskId:       taskId,
+		Tasks:        []model.Task{task},
+		Dependencies: []model.TaskDependency{},
+	}
+	blockersOf := map[uint][]uint{}
+	queue := []uint{taskId}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		blockers, err := t.deps.FindBlockers(ctx, current)
+		if err != nil {
+			return model.DependencyGraph{}, err
+		}
+		blockersOf[current] = nil
+		for _, blockerId := range blockers {
+			if _, seen := blockersOf[blockerId]; !seen && !slices.Contains(queue, blockerId) {
+				blocker, err := t.repo.FindById(ctx, blockerId)
+				if errors.Is(err, ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					return model.DependencyGraph{}, err
+				}
+				graph.Tasks = append(graph.Tasks, blocker)
+				queue = append(queue, blockerId)
+			}
+			blockersOf[current] = append(blockersOf[current], blockerId)
+			graph.Dependencies = append(graph.Dependencies, model.TaskDependency{TaskId: current, BlockerId: blockerId})
+		}
+	}
+	graph.Order = topologicalOrder(blockersOf)
+	return graph, nil
+}
+
+// topologicalOrder orders the tasks of a dependency graph so that blockers come first.
+// Tasks that become available at the same time are ordered by id, which keeps the result stable.
+func topologicalOrder(blockersOf map[uint][]uint) []uint {
+	remaining := map[uint]int{}
+	dependents := map[uint][]uint{}
+	for taskId, blockers := range blockersOf {
+		remaining[taskId] = len(blockers)
+		for _, blockerId := range blockers {
+			dependents[blockerId] = append(dependents[blockerId], taskId)
+		}
+	}
+
+	var ready []uint
+	for taskId, count := range remaining {
+		if count == 0 {
+			ready = append(ready, taskId)
+		}
+	}
+	order := make([]uint, 0, len(remaining))
+	for len(ready) > 0 {
+		slices.Sort(ready)
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+		for _, dependent := range dependents[current] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	return order
+}
+
+// dependsOn reports whether a task transitively depends on another task.
+// Dependencies through tasks in the trash count as well, so that restoring a task never closes a cycle.
+func (t *TaskService) dependsOn(ctx context.Context, taskId, otherId uint) (bool, error) {
+	visited := map[uint]bool{taskId: true}
+	stack := []uint{taskId}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		blockers, err := t.deps.FindBlockers(ctx, current)
+		if err != nil {
+			return false, err
+		}
+		for _, blockerId := range blockers {
+			if blockerId == otherId {
+				return true, nil
+			}
+			if !visited[blockerId] {
+				visited[blockerId] = true
+				stack = append(stack, blockerId)
+			}
+		}
+	}
+	return false, nil
+}
+
+// checkBlockers returns ErrBlocked when a task moves to InProgress or Done while a direct blocker is still open.
+// Blockers that are done, cancelled or in the trash no longer hold the task back.
+func (t *TaskService) checkBlockers(ctx context.Context, taskId uint, from, to model.Status) error {
+	if t.deps == nil || from == to || (to != model.StatusInProgress && to != model.StatusDone) {
+		return nil
+	}
+	blockers, err := t.deps.FindBlockers(ctx, taskId)
+	if err != nil {
+		return err
+	}
+	var open []uint
+	for _, blockerId := range blockers {
+		blocker, err := t.repo.FindById(ctx, blockerId)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if blocker.Status != model.StatusDone && blocker.Status != model.StatusCancelled {
+			open = append(open, blockerId)
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w %v", ErrBlocked, open)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDependencyTestService() *TaskService {
+	return NewTaskService(repository.NewMockTaskRepository(), WithDependencies(repository.NewMockDependencyRepository()))
+}
+
+func TestTaskService_DependenciesBlockStatusChanges(t *testing.T) {
+	taskService := newDependencyTestService()
+	blocker, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Design"})
+	task, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Build"})
+
+	_, err := taskService.AddDependency(t.Context(), task.Id, blocker.Id)
+	assert.NoError(t, err)
+
+	_, err = taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Build", Status: model.StatusInProgress})
+	assert.ErrorIs(t, err, ErrBlocked)
+
+	_, err = taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Build", Status: model.StatusCancelled})
+	assert.NoError(t, err)
+	_, err = taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Build", Status: model.StatusNew})
+	assert.NoError(t, err)
+
+	_, err = taskService.UpdateTask(t.Context(), blocker.Id, model.Task{Name: "Design", Status: model.StatusDone})
+	assert.NoError(t, err)
+	_, err = taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Build", Status: model.StatusInProgress})
+	assert.NoError(t, err)
+
+	assert.NoError(t, taskService.RemoveDependency(t.Context(), task.Id, blocker.Id))
+	assert.Error(t, taskService.RemoveDependency(t.Context(), task.Id, blocker.Id))
+}
+
+func TestTaskService_DependencyCycle(t *testing.T) {
+	taskService := newDependencyTestService()
+	a, _ := taskService.CreateTask(t.Context(), model.Task{Name: "A"})
+	b, _ := taskService.CreateTask(t.Context(), model.Task{Name: "B"})
+	c, _ := taskService.CreateTask(t.Context(), model.Task{Name: "C"})
+
+	_, err := taskService.AddDependency(t.Context(), b.Id, a.Id)
+	assert.NoError(t, err)
+	_, err = taskService.AddDependency(t.Context(), c.Id, b.Id)
+	assert.NoError(t, err)
+
+	_, err = taskService.AddDependency(t.Context(), a.Id, c.Id)
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	_, err = taskService.AddDependency(t.Context(), a.Id, a.Id)
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	_, err = taskService.AddDependency(t.Context(), a.Id, 999)
+	assert.Error(t, err)
+}
+
+func TestTaskService_GetDependencyGraph(t *testing.T) {
+	taskService := newDependencyTestService()
+	release, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Release"})
+	docs, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Docs"})
+	build, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Build"})
+	design, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Design"})
+	taskService.CreateTask(t.Context(), model.Task{Name: "Unrelated"})
+
+	taskService.AddDependency(t.Context(), release.Id, docs.Id)
+	taskService.AddDependency(t.Context(), release.Id, build.Id)
+	taskService.AddDependency(t.Context(), build.Id, design.Id)
+	taskService.AddDependency(t.Context(), docs.Id, design.Id)
+
+	graph, err := taskService.GetDependencyGraph(t.Context(), release.Id)
+	assert.NoError(t, err)
+	assert.Len(t, graph.Tasks, 4)
+	assert.Len(t, graph.Dependencies, 4)
+	assert.Equal(t, []uint{design.Id, docs.Id, build.Id, release.Id}, graph.Order)
+
+	assert.NoError(t, taskService.DeleteById(t.Context(), design.Id))
+	graph, err = taskService.GetDependencyGraph(t.Context(), release.Id)
+	assert.NoError(t, err)
+	assert.Len(t, graph.Dependencies, 2)
+
+	_, err = NewTaskService(repository.NewMockTaskRepository()).GetDependencyGraph(t.Context(), release.Id)
+	assert.ErrorIs(t, err, ErrDependenciesDisabled)
+}
+
+func TestTaskService_DependenciesInTrash(t *testing.T) {
+	deps := repository.NewMockDependencyRepository()
+	taskService := NewTaskService(repository.NewMockTaskRepository(), WithDependencies(deps))
+	task, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Task"})
+	blocker, _ := taskService.CreateTask(t.Context(), model.Task{Name: "Blocker"})
+	_, err := taskService.AddDependency(t.Context(), task.Id, blocker.Id)
+	assert.NoError(t, err)
+
+	assert.NoError(t, taskService.DeleteById(t.Context(), blocker.Id))
+	graph, err := taskService.GetDependencyGraph(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, graph.Dependencies)
+	assert.Equal(t, []uint{task.Id}, graph.Order)
+	assert.NoError(t, taskService.checkBlockers(t.Context(), task.Id, model.StatusNew, model.StatusInProgress))
+
+	_, err = taskService.RestoreTask(t.Context(), blocker.Id)
+	assert.NoError(t, err)
+	graph, err = taskService.GetDependencyGraph(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskDependency{{TaskId: task.Id, BlockerId: blocker.Id}}, graph.Dependencies)
+	_, err = taskService.UpdateTask(t.Context(), task.Id, model.Task{Name: "Task", Status: model.StatusInProgress})
+	assert.ErrorIs(t, err, ErrBlocked)
+
+	assert.NoError(t, taskService.DeleteById(t.Context(), blocker.Id))
+	_, err = taskService.PurgeTrash(t.Context(), time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	blockers, err := deps.FindBlockers(t.Context(), task.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, blockers)
+}
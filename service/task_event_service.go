@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"time"
+)
+
+// ErrEventsDisabled is returned by the operations of a TaskEventService without an event repository.
+var ErrEventsDisabled = model.NewError(errors.ErrUnsupported, "tasks are not stored as events")
+
+// TaskEventService provides access to the event log of tasks stored by a TaskEventRepositoryInterface.
+// The changes themselves are made through TaskService, which works with any task repository.
+type TaskEventService struct {
+	repo repository.TaskEventRepositoryInterface
+}
+
+// NewTaskEventService creates a new instance of TaskEventService with the specified repository.
+// A nil repository makes every operation fail with ErrEventsDisabled.
+func NewTaskEventService(repo repository.TaskEventRepositoryInterface) *TaskEventService {
+	return &TaskEventService{repo: repo}
+}
+
+// Events returns the events of a task in the order they were recorded.
+// The events outlive the task, so they are available for purged tasks as well.
+func (s *TaskEventService) Events(ctx context.Context, taskId uint) ([]model.TaskEvent, error) {
+	if s.repo == nil {
+		return nil, ErrEventsDisabled
+	}
+	return s.repo.FindEvents(ctx, taskId)
+}
+
+// TaskAt returns a task as it was at the given moment, including whether it was in the trash.
+// Returns a not found error if the task did not exist at that moment.
+func (s *TaskEventService) TaskAt(ctx context.Context, id uint, at time.Time) (model.Task, error) {
+	if s.repo == nil {
+		return model.Task{}, ErrEventsDisabled
+	}
+	return s.repo.FindAt(ctx, id, at)
+}
+
+// RebuildProjection replaces the current tasks with the state replayed from their events.
+// Returns the number of tasks that were replayed.
+func (s *TaskEventService) RebuildProjection(ctx context.Context) (int, error) {
+	if s.repo == nil {
+		return 0, ErrEventsDisabled
+	}
+	return s.repo.RebuildProjection(ctx)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func eventTypes(events []model.TaskEvent) []model.TaskEventType {
+	types := make([]model.TaskEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestTaskEventService_Events(t *testing.T) {
+	repo := repository.NewMockTaskEventRepository()
+	taskService := NewTaskService(repo)
+	eventService := NewTaskEventService(repo)
+
+	created, err := taskService.CreateTask(t.Context(), model.Task{Name: "Task", Assignee: "alex"})
+	assert.NoError(t, err)
+	beforeChanges := time.Now()
+	renamed, err := taskService.UpdateTask(t.Context(), created.Id, model.Task{Name: "Renamed", Status: model.StatusInProgress, Priority: model.PriorityHigh})
+	assert.NoError(t, err)
+	assert.NoError(t, taskService.DeleteById(t.Context(), created.Id))
+	_, err = taskService.RestoreTask(t.Context(), created.Id)
+	assert.NoError(t, err)
+
+	events, err := eventService.Events(t.Context(), created.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TaskEventType{
+		model.TaskCreated, model.TaskRenamed, model.StatusChanged, model.TaskUpdated, model.TaskDeleted, model.TaskRestored,
+	}, eventTypes(events))
+	for i, event := range events {
+		assert.Equal(t, uint(i+1), event.Sequence)
+	}
+	assert.Equal(t, "Renamed", events[1].Fields["Name"])
+	assert.Equal(t, model.TaskEventFields{"Priority": "High", "Assignee": "", "Version": renamed.Version, "UpdatedAt": renamed.UpdatedAt}, events[3].Fields)
+
+	original, err := eventService.TaskAt(t.Context(), created.Id, beforeChanges)
+	assert.NoError(t, err)
+	assert.Equal(t, "Task", original.Name)
+	assert.Equal(t, "alex", original.Assignee)
+	assert.Equal(t, model.StatusNew, original.Status)
+	assert.Equal(t, uint(1), original.Version)
+
+	deleted, err := eventService.TaskAt(t.Context(), created.Id, events[4].At)
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", deleted.Name)
+	assert.Equal(t, model.PriorityHigh, deleted.Priority)
+	assert.True(t, deleted.DeletedAt.Valid)
+
+	_, err = eventService.TaskAt(t.Context(), created.Id, created.CreatedAt.Add(-time.Second))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestTaskEventService_RebuildProjection(t *testing.T) {
+	repo := repository.NewMockTaskEventRepository()
+	taskService := NewTaskService(repo)
+	eventService := NewTaskEventService(repo)
+
+	parent := createSubtask(t, taskService, "Parent", nil)
+	child := createSubtask(t, taskService, "Child", &parent)
+	assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+	_, err := taskService.PurgeTrash(t.Context(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	done, err := taskService.UpdateTask(t.Context(), child.Id, model.Task{Name: "Child", Status: model.StatusDone})
+	assert.NoError(t, err)
+
+	events, err := eventService.Events(t.Context(), parent.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TaskPurged, events[len(events)-1].Type)
+	_, err = eventService.TaskAt(t.Context(), parent.Id, time.Now())
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = eventService.TaskAt(t.Context(), parent.Id, events[len(events)-2].At)
+	assert.NoError(t, err)
+
+	rebuilt, err := eventService.RebuildProjection(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rebuilt)
+	replayed, err := taskService.GetTaskByID(t.Context(), child.Id)
+	assert.NoError(t, err)
+	assert.Nil(t, replayed.ParentId)
+	assert.Equal(t, model.StatusDone, replayed.Status)
+	assert.Equal(t, done.Version, replayed.Version)
+	assert.True(t, done.UpdatedAt.Equal(replayed.UpdatedAt))
+	_, err = taskService.GetTaskByID(t.Context(), parent.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestTaskEventService_Disabled(t *testing.T) {
+	eventService := NewTaskEventService(nil)
+
+	_, err := eventService.Events(t.Context(), 1)
+	assert.ErrorIs(t, err, ErrEventsDisabled)
+	_, err = eventService.TaskAt(t.Context(), 1, time.Now())
+	assert.ErrorIs(t, err, ErrEventsDisabled)
+	_, err = eventService.RebuildProjection(t.Context())
+	assert.ErrorIs(t, err, ErrEventsDisabled)
+}
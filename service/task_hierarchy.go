@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"task_manager_go/model"
+)
+
+// ChildDeletePolicy decides what happens to the subtasks of a task that is deleted.
+type ChildDeletePolicy string
+
+const (
+	// DeleteCascade deletes the whole subtree together with the task.
+	DeleteCascade ChildDeletePolicy = "cascade"
+	// DeleteOrphan detaches the subtasks, turning them into top-level tasks.
+	DeleteOrphan ChildDeletePolicy = "orphan"
+	// DeleteReject refuses to delete a task that still has subtasks.
+	DeleteReject ChildDeletePolicy = "reject"
+)
+
+var (
+	// ErrParentCycle is returned when a parent change would make a task its own ancestor.
+	ErrParentCycle = model.NewError(model.ErrConflict, "task cannot be a subtask of itself or of its subtasks")
+	// ErrHasSubtasks is returned when a task with subtasks is deleted under the DeleteReject policy.
+	ErrHasSubtasks = model.NewError(model.ErrConflict, "task has subtasks")
+)
+
+// ParseChildDeletePolicy returns the policy with the given name.
+func ParseChildDeletePolicy(value string) (ChildDeletePolicy, error) {
+	switch policy := ChildDeletePolicy(value); policy {
+	case DeleteCascade, DeleteOrphan, DeleteReject:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown child delete policy %q", value)
+	}
+}
+
+// GetSubtasks returns the direct subtasks of a task.
+// Returns an error if the task was not found or another error occurred.
+func (t *TaskService) GetSubtasks(ctx context.Context, id uint) (subtasks []model.Task, err error) {
+	defer t.observe("get_subtasks", &err)
+	if _, err := t.repo.FindById(ctx, id); err != nil {
+		return nil, err
+	}
+	return t.repo.FindChildren(ctx, id)
+}
+
+// GetSubtree returns a task with all of its descendants and their progress.
+// Returns an error if the task was not found or another error occurred.
+func (t *TaskService) GetSubtree(ctx context.Context, id uint) (subtree model.TaskNode, err error) {
+	defer t.observe("get_subtree", &err)
+	task, err := t.repo.FindById(ctx, id)
+	if err != nil {
+		return model.TaskNode{}, err
+	}
+	return t.buildSubtree(ctx, task, map[uint]bool{})
+}
+
+// buildSubtree loads the descendants of task depth-first.
+// visited guards against cycles in data written before cycles were prevented.
+func (t *TaskService) buildSubtree(ctx context.Context, task model.Task, visited map[uint]bool) (model.TaskNode, error) {
+	visited[task.Id] = true
+	node := model.TaskNode{Task: task, Children: []model.TaskNode{}}
+
+	children, err := t.repo.FindChildren(ctx, task.Id)
+	if err != nil {
+		return model.TaskNode{}, err
+	}
+	for _, child := range children {
+		if visited[child.Id] {
+			continue
+		}
+		childNode, err := t.buildSubtree(ctx, child, visited)
+		if err != nil {
+			return model.TaskNode{}, err
+		}
+		node.Children = append(node.Children, childNode)
+	}
+	node.Progress = progress(node)
+	return node, nil
+}
+
+// progress computes the completion of a task in percent.
+// A task without subtasks is either done or not, a parent averages the progress of its subtasks.
+// Cancelled subtasks do not count towards the progress of their parent.
+func progress(node model.TaskNode) float64 {
+	var sum float64
+	var counted int
+	for _, child := range node.Children {
+		if child.Status == model.StatusCancelled {
+			continue
+		}
+		sum += child.Progress
+		counted++
+	}
+	if counted > 0 {
+		return sum / float64(counted)
+	}
+	if node.Status == model.StatusDone {
+		return 100
+	}
+	return 0
+}
+
+// checkParent verifies that the parent of a task exists and is not the task itself or one of its descendants.
+// id is 0 for a task that is being created.
+func (t *TaskService) checkParent(ctx context.Context, id uint, parentId *uint) error {
+	if parentId == nil {
+		return nil
+	}
+	visited := map[uint]bool{}
+	for current := parentId; current != nil; {
+		if *current == id {
+			return ErrParentCycle
+		}
+		if visited[*current] {
+			// the existing hierarchy already has a cycle that does not involve this task
+			return ErrParentCycle
+		}
+		visited[*current] = true
+
+		ancestor, err := t.repo.FindById(ctx, *current)
+		if err != nil {
+			return fmt.Errorf("%w: parent task %d does not exist", ErrInvalidTask, *current)
+		}
+		current = ancestor.ParentId
+	}
+	return nil
+}
+
+// deleteChildren applies the child delete policy to the subtasks of a task that is about to be deleted.
+// visited guards the cascade against cycles in data written before cycles were prevented.
+func (t *TaskService) deleteChildren(ctx context.Context, id uint, visited map[uint]bool) error {
+	visited[id] = true
+	children, err := t.repo.FindChildren(ctx, id)
+	if err != nil || len(children) == 0 {
+		return err
+	}
+	switch t.childDeletePolicy {
+	case DeleteReject:
+		return fmt.Errorf("%w: delete or move its %d subtasks first", ErrHasSubtasks, len(children))
+	case DeleteCascade:
+		for _, child := range children {
+			if visited[child.Id] {
+				continue
+			}
+			if err := t.deleteChildren(ctx, child.Id, visited); err != nil {
+				return err
+			}
+			if err := t.deleteTask(ctx, child, 0); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, child := range children {
+			detached := child
+			detached.ParentId = nil
+			saved, err := t.repo.UpdateTaskById(ctx, child.Id, detached)
+			if err != nil {
+				return err
+			}
+			if err := t.record(ctx, model.AuditUpdate, child.Id, &child, &saved); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createSubtask(t *testing.T, taskService *TaskService, name string, parent *model.Task) model.Task {
+	task := model.Task{Name: name}
+	if parent != nil {
+		task.ParentId = &parent.Id
+	}
+	created, err := taskService.CreateTask(t.Context(), task)
+	assert.NoError(t, err)
+	return created
+}
+
+func TestTaskService_GetSubtree(t *testing.T) {
+	taskService := NewTaskService(repository.NewMockTaskRepository())
+
+	epic := createSubtask(t, taskService, "Epic", nil)
+	first := createSubtask(t, taskService, "First", &epic)
+	second := createSubtask(t, taskService, "Second", &epic)
+	createSubtask(t, taskService, "Step", &second)
+	step := createSubtask(t, taskService, "Other step", &second)
+	cancelled := createSubtask(t, taskService, "Dropped", &epic)
+
+	_, err := taskService.UpdateTask(t.Context(), first.Id, model.Task{Name: "First", Status: model.StatusDone, ParentId: &epic.Id})
+	assert.NoError(t, err)
+	_, err = taskService.UpdateTask(t.Context(), step.Id, model.Task{Name: "Other step", Status: model.StatusDone, ParentId: &second.Id})
+	assert.NoError(t, err)
+	_, err = taskService.UpdateTask(t.Context(), cancelled.Id, model.Task{Name: "Dropped", Status: model.StatusCancelled, ParentId: &epic.Id})
+	assert.NoError(t, err)
+
+	subtasks, err := taskService.GetSubtasks(t.Context(), epic.Id)
+	assert.NoError(t, err)
+	assert.Len(t, subtasks, 3)
+
+	tree, err := taskService.GetSubtree(t.Context(), epic.Id)
+	assert.NoError(t, err)
+	assert.Len(t, tree.Children, 3)
+	assert.Len(t, tree.Children[1].Children, 2)
+	assert.Equal(t, 50.0, tree.Children[1].Progress)
+	assert.Equal(t, 75.0, tree.Progress)
+}
+
+func TestTaskService_ParentCycle(t *testing.T) {
+	taskService := NewTaskService(repository.NewMockTaskRepository())
+
+	root := createSubtask(t, taskService, "Root", nil)
+	child := createSubtask(t, taskService, "Child", &root)
+	grandchild := createSubtask(t, taskService, "Grandchild", &child)
+
+	_, err := taskService.UpdateTask(t.Context(), root.Id, model.Task{Name: "Root", ParentId: &grandchild.Id})
+	assert.ErrorIs(t, err, ErrParentCycle)
+
+	_, err = taskService.UpdateTask(t.Context(), root.Id, model.Task{Name: "Root", ParentId: &root.Id})
+	assert.ErrorIs(t, err, ErrParentCycle)
+
+	missing := uint(999)
+	_, err = taskService.CreateTask(t.Context(), model.Task{Name: "Lost", ParentId: &missing})
+	assert.ErrorIs(t, err, ErrInvalidTask)
+}
+
+func TestTaskService_DeleteWithSubtasks(t *testing.T) {
+	t.Run("orphan", func(t *testing.T) {
+		taskService := NewTaskService(repository.NewMockTaskRepository())
+		parent := createSubtask(t, taskService, "Parent", nil)
+		child := createSubtask(t, taskService, "Child", &parent)
+
+		assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+		orphan, err := taskService.GetTaskByID(t.Context(), child.Id)
+		assert.NoError(t, err)
+		assert.Nil(t, orphan.ParentId)
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		taskService := NewTaskService(repository.NewMockTaskRepository(), WithChildDeletePolicy(DeleteCascade))
+		parent := createSubtask(t, taskService, "Parent", nil)
+		child := createSubtask(t, taskService, "Child", &parent)
+		grandchild := createSubtask(t, taskService, "Grandchild", &child)
+
+		assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+		_, err := taskService.GetTaskByID(t.Context(), child.Id)
+		assert.Error(t, err)
+		_, err = taskService.GetTaskByID(t.Context(), grandchild.Id)
+		assert.Error(t, err)
+	})
+
+	t.Run("cascade through a cycle", func(t *testing.T) {
+		repo := repository.NewMockTaskRepository()
+		taskService := NewTaskService(repo, WithChildDeletePolicy(DeleteCascade))
+		parent := createSubtask(t, taskService, "Parent", nil)
+		child := createSubtask(t, taskService, "Child", &parent)
+		// a cycle written before cycles were prevented
+		parent.ParentId = &child.Id
+		_, err := repo.UpdateTaskById(t.Context(), parent.Id, parent)
+		assert.NoError(t, err)
+
+		assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+		trash, err := taskService.GetTrash(t.Context())
+		assert.NoError(t, err)
+		assert.Len(t, trash, 2)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		taskService := NewTaskService(repository.NewMockTaskRepository(), WithChildDeletePolicy(DeleteReject))
+		parent := createSubtask(t, taskService, "Parent", nil)
+		createSubtask(t, taskService, "Child", &parent)
+
+		assert.ErrorIs(t, taskService.DeleteById(t.Context(), parent.Id), ErrHasSubtasks)
+		_, err := taskService.GetTaskByID(t.Context(), parent.Id)
+		assert.NoError(t, err)
+	})
+
+	_, err := ParseChildDeletePolicy("drop")
+	assert.Error(t, err)
+}
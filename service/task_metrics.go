@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task_manager_go/metrics"
+	"task_manager_go/model"
+)
+
+// WithMetrics counts the operations of the service and their errors by kind in registry.
+// Services sharing a registry share the counters; see RegisterTaskGauge for the number of tasks.
+func WithMetrics(registry *metrics.Registry) Option {
+	return func(t *TaskService) {
+		t.operations = registry.Counter("task_service_operations_total",
+			"Number of task service operations by operation.", "operation")
+		t.operationErrors = registry.Counter("task_service_errors_total",
+			"Number of failed task service operations by operation and kind of error.", "operation", "kind")
+	}
+}
+
+// RegisterTaskGauge exposes the number of tasks by status in registry, counted by the service on every scrape.
+// A registry has a single tasks gauge, so it is registered once, by the service the server is built on.
+// Panics if the gauge is already registered.
+func (t *TaskService) RegisterTaskGauge(registry *metrics.Registry) {
+	registry.GaugeFunc("tasks", "Number of tasks by status, without deleted tasks.", []string{"status"},
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			counts, err := t.CountTasksByStatus(ctx)
+			if err != nil {
+				return nil, err
+			}
+			samples := make([]metrics.Sample, 0, len(counts))
+			for status, count := range counts {
+				samples = append(samples, metrics.Sample{LabelValues: []string{string(status)}, Value: float64(count)})
+			}
+			return samples, nil
+		})
+}
+
+// CountTasksByStatus returns the number of tasks with every status, including statuses no task has.
+func (t *TaskService) CountTasksByStatus(ctx context.Context) (map[model.Status]int64, error) {
+	counts, err := t.repo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range model.Statuses {
+		if _, counted := counts[status]; !counted {
+			counts[status] = 0
+		}
+	}
+	return counts, nil
+}
+
+// observe counts the operation op and, if *err is not nil, its error when the service has metrics.
+// It is deferred by every operation with a pointer to its error result.
+func (t *TaskService) observe(op string, err *error) {
+	if t.operations == nil {
+		return
+	}
+	t.operations.Inc(op)
+	if *err != nil {
+		t.operationErrors.Inc(op, errorKind(*err))
+	}
+}
+
+// errorKind returns the label of the kind of an error of an operation.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrValidation):
+		return "validation"
+	case errors.Is(err, ErrConflict):
+		return "conflict"
+	case errors.Is(err, ErrPreconditionFailed):
+		return "precondition_failed"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "timeout"
+	default:
+		return "internal"
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"strings"
+	"task_manager_go/metrics"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskService_Metrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	taskService := NewTaskService(repository.NewMockTaskRepository(), WithMetrics(registry))
+	taskService.RegisterTaskGauge(registry)
+	other := NewTaskService(repository.NewMockTaskRepository(), WithMetrics(registry))
+
+	task, err := taskService.CreateTask(t.Context(), model.Task{Name: "Task"})
+	assert.NoError(t, err)
+	_, err = taskService.CreateTask(t.Context(), model.Task{Name: "Started", Status: model.StatusInProgress})
+	assert.NoError(t, err)
+	_, err = taskService.CreateTask(t.Context(), model.Task{Name: "Unknown", Status: "Unknown"})
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = taskService.GetTaskDetails(t.Context(), task.Id)
+	assert.NoError(t, err)
+	_, err = taskService.GetTaskDetails(t.Context(), task.Id+100)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = other.CreateTask(t.Context(), model.Task{Name: "Other"})
+	assert.NoError(t, err)
+
+	var out strings.Builder
+	assert.NoError(t, registry.Write(t.Context(), &out))
+	for _, line := range []string{
+		`task_service_operations_total{operation="create_task"} 4`,
+		`task_service_operations_total{operation="get_task_details"} 2`,
+		`task_service_errors_total{operation="create_task",kind="validation"} 1`,
+		`task_service_errors_total{operation="get_task_details",kind="not_found"} 1`,
+		`tasks{status="New"} 1`,
+		`tasks{status="InProgress"} 1`,
+		`tasks{status="Done"} 0`,
+	} {
+		assert.Contains(t, out.String(), line+"\n")
+	}
+	assert.NotContains(t, out.String(), `operation="get_task"}`)
+}
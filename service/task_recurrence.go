@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"task_manager_go/model"
+	"time"
+)
+
+const (
+	// DefaultOccurrencePreview is the number of occurrences PreviewOccurrences returns when no count is given.
+	DefaultOccurrencePreview = 5
+	// MaxOccurrencePreview is the largest number of occurrences PreviewOccurrences returns.
+	MaxOccurrencePreview = 100
+)
+
+// PreviewOccurrences returns up to count upcoming occurrences of a recurring task after the task itself,
+// in the time zone of its recurrence. A task that does not recur has no upcoming occurrences.
+// Returns an error if the task was not found or another error occurred.
+func (t *TaskService) PreviewOccurrences(ctx context.Context, id uint, count int) (occurrences []time.Time, err error) {
+	defer t.observe("preview_occurrences", &err)
+	if count < 0 {
+		return nil, fmt.Errorf("%w: count must not be negative", ErrInvalidQuery)
+	}
+	if count == 0 {
+		count = DefaultOccurrencePreview
+	}
+	if count > MaxOccurrencePreview {
+		count = MaxOccurrencePreview
+	}
+	task, err := t.repo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rule, loc, err := recurrenceOf(task)
+	if err != nil || rule == nil {
+		return []time.Time{}, err
+	}
+	return rule.Occurrences(occurrenceAnchor(task), task.Occurrence, count, loc), nil
+}
+
+// scheduleNextOccurrence creates the occurrence following a recurring task that was just done
+// and links it from the task. Nothing is created when the series has ended or the next occurrence already exists,
+// for example because the task was reopened and done again.
+func (t *TaskService) scheduleNextOccurrence(ctx context.Context, task model.Task) (model.Task, error) {
+	if task.NextOccurrenceId != nil {
+		return task, nil
+	}
+	rule, loc, err := recurrenceOf(task)
+	if err != nil || rule == nil {
+		return task, err
+	}
+	upcoming := rule.Occurrences(occurrenceAnchor(task), task.Occurrence, 1, loc)
+	if len(upcoming) == 0 {
+		return task, nil
+	}
+
+	next := model.Task{
+		Name:        task.Name,
+		Description: task.Description,
+		Status:      model.StatusNew,
+		Priority:    task.Priority,
+		Assignee:    task.Assignee,
+		ParentId:    task.ParentId,
+		Recurrence:  task.Recurrence,
+		TimeZone:    task.TimeZone,
+		Occurrence:  task.Occurrence + 1,
+	}
+	if task.DueDate != nil {
+		next.DueDate = &upcoming[0]
+	} else {
+		next.Date = upcoming[0]
+	}
+	created, err := t.repo.CreateTask(ctx, next)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if err := t.record(ctx, model.AuditCreate, created.Id, nil, &created); err != nil {
+		return model.Task{}, err
+	}
+	linked := task
+	linked.NextOccurrenceId = &created.Id
+	saved, err := t.repo.UpdateTaskById(ctx, task.Id, linked)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return saved, t.record(ctx, model.AuditUpdate, task.Id, &task, &saved)
+}
+
+// recurrenceOf parses the recurrence of a task and loads its time zone.
+// The rule is nil when the task does not recur.
+func recurrenceOf(task model.Task) (*RecurrenceRule, *time.Location, error) {
+	loc, err := time.LoadLocation(task.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidRecurrence, task.TimeZone)
+	}
+	if task.Recurrence == "" {
+		return nil, loc, nil
+	}
+	rule, err := ParseRecurrenceRule(task.Recurrence, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &rule, loc, nil
+}
+
+// occurrenceAnchor returns the moment a task occurs at: its due date, or its date when it has none.
+func occurrenceAnchor(task model.Task) time.Time {
+	if task.DueDate != nil {
+		return *task.DueDate
+	}
+	return task.Date
+}
@@ -1,73 +1,360 @@
 package service
 
 import (
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"strings"
+	"task_manager_go/metrics"
 	"task_manager_go/model"
 	"task_manager_go/repository"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultPageSize is the number of tasks returned by FindTasks when the query has no limit.
+	DefaultPageSize = 50
+	// MaxPageSize is the largest page FindTasks will return.
+	MaxPageSize = 1000
+)
+
+const (
+	// MaxNameLength is the longest task name accepted.
+	MaxNameLength = 255
+	// MaxDescriptionLength is the longest task description accepted.
+	MaxDescriptionLength = 10000
+	// MaxAssigneeLength is the longest assignee name accepted.
+	MaxAssigneeLength = 255
+)
+
+var (
+	// ErrInvalidQuery is returned when a task query has inconsistent or unsupported parameters.
+	ErrInvalidQuery = model.NewError(model.ErrValidation, "invalid task query")
+	// ErrInvalidTask is returned when task fields are out of range or inconsistent.
+	ErrInvalidTask = model.NewError(model.ErrValidation, "invalid task")
+	// ErrVersionMismatch is returned when a change is based on a version of a task that is no longer current.
+	ErrVersionMismatch = model.NewError(model.ErrPreconditionFailed, "task has been changed since the given version")
 )
 
 // TaskService provides business logic for task management.
 // Uses TaskRepositoryInterface for data storage interaction.
 type TaskService struct {
-	repo repository.TaskRepositoryInterface
+	repo              repository.TaskRepositoryInterface
+	deps              repository.DependencyRepositoryInterface
+	comments          repository.CommentRepositoryInterface
+	checklists        repository.ChecklistRepositoryInterface
+	audit             repository.AuditRepositoryInterface
+	transactor        repository.Transactor
+	childDeletePolicy ChildDeletePolicy
+	actor             string
+	operations        *metrics.Counter
+	operationErrors   *metrics.Counter
+}
+
+// Option configures optional behavior of a TaskService.
+type Option func(*TaskService)
+
+// WithChildDeletePolicy sets what happens to the subtasks of a deleted task.
+// The default is DeleteOrphan.
+func WithChildDeletePolicy(policy ChildDeletePolicy) Option {
+	return func(t *TaskService) {
+		t.childDeletePolicy = policy
+	}
+}
+
+// WithDependencies enables dependencies between tasks stored in the given repository.
+// Without it, dependency operations fail with ErrDependenciesDisabled and status changes are never blocked.
+func WithDependencies(deps repository.DependencyRepositoryInterface) Option {
+	return func(t *TaskService) {
+		t.deps = deps
+	}
+}
+
+// WithComments makes deleting a task delete its comments from the given repository as well.
+func WithComments(comments repository.CommentRepositoryInterface) Option {
+	return func(t *TaskService) {
+		t.comments = comments
+	}
+}
+
+// WithChecklists includes the checklist of a task from the given repository in GetTaskDetails
+// and makes deleting a task delete its checklist as well.
+func WithChecklists(checklists repository.ChecklistRepositoryInterface) Option {
+	return func(t *TaskService) {
+		t.checklists = checklists
+	}
+}
+
+// WithAudit records every change of a task made through the service in the given repository, see As.
+func WithAudit(audit repository.AuditRepositoryInterface) Option {
+	return func(t *TaskService) {
+		t.audit = audit
+	}
+}
+
+// WithTransactor makes every change of a task run in a transaction of the given Transactor,
+// so that the task, the tasks changed along with it and its audit entries are stored together or not at all.
+func WithTransactor(transactor repository.Transactor) Option {
+	return func(t *TaskService) {
+		t.transactor = transactor
+	}
 }
 
-// NewTaskService creates a new instance of TaskService with the specified repository.
-func NewTaskService(repo repository.TaskRepositoryInterface) *TaskService {
-	return &TaskService{repo: repo}
+// NewTaskService creates a new instance of TaskService with the specified repository and options.
+func NewTaskService(repo repository.TaskRepositoryInterface, opts ...Option) *TaskService {
+	t := &TaskService{repo: repo, childDeletePolicy: DeleteOrphan, actor: DefaultActor}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // CreateTask creates a new task in the system.
-// Returns the created task and an error if one occurred.
-func (t *TaskService) CreateTask(task model.Task) (model.Task, error) {
-	return t.repo.CreateTask(task)
+// Tasks without a status start as New, tasks without a priority get Medium.
+// A recurring task is the first occurrence of its series.
+// Returns the created task and an error if the task is invalid or another error occurred.
+func (t *TaskService) CreateTask(ctx context.Context, task model.Task) (created model.Task, err error) {
+	defer t.observe("create_task", &err)
+	err = t.inTransaction(ctx, func(tx *TaskService) error {
+		created, err = tx.createTask(ctx, task)
+		return err
+	})
+	return created, err
+}
+
+// createTask creates a task and records it in the audit trail.
+func (t *TaskService) createTask(ctx context.Context, task model.Task) (model.Task, error) {
+	if task.Status == "" {
+		task.Status = model.StatusNew
+	}
+	if task.Priority == 0 {
+		task.Priority = model.PriorityMedium
+	}
+	task.Occurrence = 1
+	task.NextOccurrenceId = nil
+	if !task.Status.IsValid() {
+		return model.Task{}, fmt.Errorf("%w %q", model.ErrUnknownStatus, task.Status)
+	}
+	if err := validateTask(task); err != nil {
+		return model.Task{}, err
+	}
+	if err := t.checkParent(ctx, 0, task.ParentId); err != nil {
+		return model.Task{}, err
+	}
+	created, err := t.repo.CreateTask(ctx, task)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return created, t.record(ctx, model.AuditCreate, created.Id, nil, &created)
 }
 
 // UpdateTask updates an existing task by its ID.
-// Returns the updated task and an error if the task was not found or another error occurred.
-func (t *TaskService) UpdateTask(id uint, task model.Task) (model.Task, error) {
-	updatedTask, err := t.repo.FindById(id)
+// An empty status or priority keeps the current one, any other status change must be an allowed transition.
+// Moving a task to InProgress or Done requires all of its blockers to be done or cancelled.
+// Completing a recurring task creates its next occurrence.
+// A non-zero Version is the version of the task the change is based on; the update fails if the task has changed since.
+// Returns the updated task and an error if the task was not found, the version does not match,
+// the transition is not allowed or another error occurred.
+func (t *TaskService) UpdateTask(ctx context.Context, id uint, task model.Task) (updated model.Task, err error) {
+	defer t.observe("update_task", &err)
+	err = t.inTransaction(ctx, func(tx *TaskService) error {
+		updated, err = tx.updateTask(ctx, id, task)
+		return err
+	})
+	return updated, err
+}
+
+// updateTask updates a task and records the change in the audit trail.
+func (t *TaskService) updateTask(ctx context.Context, id uint, task model.Task) (model.Task, error) {
+	updatedTask, err := t.repo.FindById(ctx, id)
 	if err != nil {
 		return model.Task{}, err
 	}
-	if updatedTask.Id == 0 {
-		log.Printf("Task with ID %d wasn't found", id)
-		return model.Task{}, errors.New("task wasn't found")
+	if task.Version != 0 && task.Version != updatedTask.Version {
+		return model.Task{}, ErrVersionMismatch
+	}
+	if task.Status == "" {
+		task.Status = updatedTask.Status
+	}
+	if task.Priority == 0 {
+		task.Priority = updatedTask.Priority
+	}
+	if err := checkTransition(updatedTask.Status, task.Status); err != nil {
+		return model.Task{}, err
+	}
+	if err := t.checkBlockers(ctx, id, updatedTask.Status, task.Status); err != nil {
+		return model.Task{}, err
 	}
-	return t.repo.UpdateTaskById(id, task)
+	task.Date = updatedTask.Date
+	task.Occurrence = updatedTask.Occurrence
+	task.NextOccurrenceId = updatedTask.NextOccurrenceId
+	if err := validateTask(task); err != nil {
+		return model.Task{}, err
+	}
+	if err := t.checkParent(ctx, id, task.ParentId); err != nil {
+		return model.Task{}, err
+	}
+	saved, err := t.repo.UpdateTaskById(ctx, id, task)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if err := t.record(ctx, model.AuditUpdate, id, &updatedTask, &saved); err != nil {
+		return model.Task{}, err
+	}
+	if updatedTask.Status != model.StatusDone && saved.Status == model.StatusDone {
+		return t.scheduleNextOccurrence(ctx, saved)
+	}
+	return saved, nil
 }
 
 // GetAllTasks returns a list of all tasks in the system.
 // Returns a slice of tasks and an error if one occurred.
-func (t *TaskService) GetAllTasks() ([]model.Task, error) {
-	return t.repo.GetAll()
+func (t *TaskService) GetAllTasks(ctx context.Context) (tasks []model.Task, err error) {
+	defer t.observe("get_all_tasks", &err)
+	return t.repo.GetAll(ctx)
+}
+
+// FindTasks returns a page of tasks matching the query.
+// Applies the default page size and rejects unknown sort fields, negative offsets and empty date ranges.
+// A query continuing from a cursor is always sorted by date and cannot be combined with an offset.
+func (t *TaskService) FindTasks(ctx context.Context, query model.TaskQuery) (page model.TaskPage, err error) {
+	defer t.observe("find_tasks", &err)
+	if query.After != nil {
+		if query.SortBy == "" {
+			query.SortBy = model.SortByDate
+		}
+		if query.SortBy != model.SortByDate {
+			return model.TaskPage{}, fmt.Errorf("%w: cursor pagination requires sorting by date", ErrInvalidQuery)
+		}
+		if query.Offset != 0 {
+			return model.TaskPage{}, fmt.Errorf("%w: cursor and offset cannot be combined", ErrInvalidQuery)
+		}
+	}
+	if query.SortBy == "" {
+		query.SortBy = model.SortById
+	}
+	if !model.IsTaskSortField(query.SortBy) {
+		return model.TaskPage{}, fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, query.SortBy)
+	}
+	if query.Limit < 0 || query.Offset < 0 {
+		return model.TaskPage{}, fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidQuery)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultPageSize
+	}
+	if query.Limit > MaxPageSize {
+		query.Limit = MaxPageSize
+	}
+	if query.CreatedAfter != nil && query.CreatedBefore != nil && !query.CreatedAfter.Before(*query.CreatedBefore) {
+		return model.TaskPage{}, fmt.Errorf("%w: created_after must be before created_before", ErrInvalidQuery)
+	}
+	if query.DueAfter != nil && query.DueBefore != nil && !query.DueAfter.Before(*query.DueBefore) {
+		return model.TaskPage{}, fmt.Errorf("%w: due_after must be before due_before", ErrInvalidQuery)
+	}
+	return t.repo.Find(ctx, query)
 }
 
 // GetTaskByID finds a task by its ID.
 // Returns the found task and an error if the task was not found or another error occurred.
-func (t *TaskService) GetTaskByID(id uint) (model.Task, error) {
-	task, err := t.repo.FindById(id)
+func (t *TaskService) GetTaskByID(ctx context.Context, id uint) (task model.Task, err error) {
+	defer t.observe("get_task", &err)
+	return t.repo.FindById(ctx, id)
+}
+
+// GetTaskDetails finds a task by its ID together with its checklist.
+// Returns the found task and an error if the task was not found or another error occurred.
+func (t *TaskService) GetTaskDetails(ctx context.Context, id uint) (details model.TaskDetails, err error) {
+	defer t.observe("get_task_details", &err)
+	task, err := t.repo.FindById(ctx, id)
 	if err != nil {
-		return model.Task{}, err
+		return model.TaskDetails{}, err
 	}
-	if task.Id == 0 {
-		log.Printf("Task with ID %d wasn't found", id)
-		return model.Task{}, errors.New("task wasn't found")
+	details = model.TaskDetails{Task: task, Checklist: model.NewChecklist(nil)}
+	if t.checklists != nil {
+		items, err := t.checklists.FindByTask(ctx, id)
+		if err != nil {
+			return model.TaskDetails{}, err
+		}
+		details.Checklist = model.NewChecklist(items)
 	}
-	return task, nil
+	return details, nil
 }
 
-// DeleteById deletes a task by its ID.
-// Returns an error if the task was not found or another error occurred.
-func (t *TaskService) DeleteById(id uint) error {
-	task, err := t.repo.FindById(id)
+// DeleteById moves a task to the trash by its ID.
+// Its subtasks are deleted, detached or protect the task according to the child delete policy.
+// Returns an error if the task was not found, still has subtasks under DeleteReject or another error occurred.
+func (t *TaskService) DeleteById(ctx context.Context, id uint) error {
+	return t.DeleteVersion(ctx, id, 0)
+}
+
+// DeleteVersion deletes a task by its ID like DeleteById, provided that it still has the given version.
+// A zero version deletes the task whatever its version.
+// Returns ErrVersionMismatch if the task has changed, otherwise the errors of DeleteById.
+func (t *TaskService) DeleteVersion(ctx context.Context, id uint, version uint) (err error) {
+	defer t.observe("delete_task", &err)
+	return t.inTransaction(ctx, func(tx *TaskService) error {
+		return tx.deleteVersion(ctx, id, version)
+	})
+}
+
+// deleteVersion deletes a task and its subtasks according to the child delete policy.
+func (t *TaskService) deleteVersion(ctx context.Context, id uint, version uint) error {
+	task, err := t.repo.FindById(ctx, id)
 	if err != nil {
 		return err
 	}
-	if task.Id == 0 {
-		log.Printf("Task with ID %d wasn't found", id)
-		return errors.New("task wasn't found")
+	if version != 0 && version != task.Version {
+		return ErrVersionMismatch
+	}
+	if err := t.deleteChildren(ctx, id, map[uint]bool{}); err != nil {
+		return err
+	}
+	return t.deleteTask(ctx, task, version)
+}
+
+// deleteTask moves a single task to the trash, provided that it still has the given version unless it is 0.
+// Its dependencies, comments and checklist are kept until the task is purged; meanwhile it blocks no other task.
+func (t *TaskService) deleteTask(ctx context.Context, task model.Task, version uint) error {
+	if err := t.repo.DeleteByID(ctx, task.Id, version); err != nil {
+		return err
+	}
+	return t.record(ctx, model.AuditDelete, task.Id, &task, nil)
+}
+
+// validateTask checks the fields of a task that are not covered by their types.
+func validateTask(task model.Task) error {
+	if strings.TrimSpace(task.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTask)
+	}
+	if utf8.RuneCountInString(task.Name) > MaxNameLength {
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidTask, MaxNameLength)
+	}
+	if !task.Priority.IsValid() {
+		return fmt.Errorf("%w %d", model.ErrUnknownPriority, task.Priority)
+	}
+	if utf8.RuneCountInString(task.Description) > MaxDescriptionLength {
+		return fmt.Errorf("%w: description is longer than %d characters", ErrInvalidTask, MaxDescriptionLength)
+	}
+	if utf8.RuneCountInString(task.Assignee) > MaxAssigneeLength {
+		return fmt.Errorf("%w: assignee is longer than %d characters", ErrInvalidTask, MaxAssigneeLength)
+	}
+	if task.DueDate != nil && !task.Date.IsZero() && task.DueDate.Before(task.Date) {
+		return fmt.Errorf("%w: due date is before the task date", ErrInvalidTask)
+	}
+	if _, _, err := recurrenceOf(task); err != nil {
+		return err
+	}
+	return nil
+}
+
+// touchTasks increments the versions of tasks whose checklist or tags changed, so that their ETags change
+// with everything GET /tasks/{id} returns. Tasks in the trash are touched as well, so that they are restored
+// with a version their old ETags do not match; tasks that were purged are skipped.
+func touchTasks(ctx context.Context, tasks repository.TaskRepositoryInterface, ids ...uint) error {
+	for _, id := range ids {
+		if _, err := tasks.Touch(ctx, id); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
 	}
-	return t.repo.DeleteByID(id)
+	return nil
 }
@@ -1,7 +1,7 @@
 package service
 
 import (
-	"errors"
+	"strings"
 	"task_manager_go/model"
 	"task_manager_go/repository"
 	"testing"
@@ -14,8 +14,8 @@ func TestTaskService_CreateTask(t *testing.T) {
 	mockRepo := repository.NewMockTaskRepository()
 	taskService := NewTaskService(mockRepo)
 
-	task := model.Task{Name: "Test Task", Status: "Pending", Date: time.Now()}
-	createdTask, err := taskService.CreateTask(task)
+	task := model.Task{Name: "Test Task", Status: model.StatusNew, Date: time.Now()}
+	createdTask, err := taskService.CreateTask(t.Context(), task)
 
 	assert.NoError(t, err)
 	assert.NotZero(t, createdTask.Id)
@@ -26,46 +26,183 @@ func TestTaskService_GetTaskByID(t *testing.T) {
 	mockRepo := repository.NewMockTaskRepository()
 	taskService := NewTaskService(mockRepo)
 
-	task := model.Task{Name: "Test Task", Status: "Pending", Date: time.Now()}
-	createdTask, _ := mockRepo.CreateTask(task)
+	task := model.Task{Name: "Test Task", Status: model.StatusNew, Date: time.Now()}
+	createdTask, _ := mockRepo.CreateTask(t.Context(), task)
 
-	foundTask, err := taskService.GetTaskByID(createdTask.Id)
+	foundTask, err := taskService.GetTaskByID(t.Context(), createdTask.Id)
 	assert.NoError(t, err)
 	assert.Equal(t, createdTask.Id, foundTask.Id)
 
-	_, err = taskService.GetTaskByID(999)
+	_, err = taskService.GetTaskByID(t.Context(), 999)
 	assert.Error(t, err)
-	assert.Equal(t, errors.New("task not found"), err)
+	assert.ErrorIs(t, err, ErrNotFound)
 }
 
 func TestTaskService_UpdateTask(t *testing.T) {
 	mockRepo := repository.NewMockTaskRepository()
 	taskService := NewTaskService(mockRepo)
 
-	task := model.Task{Name: "Original Task", Status: "Pending", Date: time.Now()}
-	createdTask, _ := mockRepo.CreateTask(task)
+	task := model.Task{Name: "Original Task", Status: model.StatusNew, Date: time.Now()}
+	createdTask, _ := mockRepo.CreateTask(t.Context(), task)
 
-	updatedTask := model.Task{Name: "Updated Task", Status: "Completed"}
-	result, err := taskService.UpdateTask(createdTask.Id, updatedTask)
+	updatedTask := model.Task{Name: "Updated Task", Status: model.StatusDone}
+	result, err := taskService.UpdateTask(t.Context(), createdTask.Id, updatedTask)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated Task", result.Name)
-	assert.Equal(t, "Completed", result.Status)
+	assert.Equal(t, model.StatusDone, result.Status)
 }
 
 func TestTaskService_DeleteById(t *testing.T) {
 	mockRepo := repository.NewMockTaskRepository()
 	taskService := NewTaskService(mockRepo)
 
-	task := model.Task{Name: "Test Task", Status: "Pending", Date: time.Now()}
-	createdTask, _ := mockRepo.CreateTask(task)
+	task := model.Task{Name: "Test Task", Status: model.StatusNew, Date: time.Now()}
+	createdTask, _ := mockRepo.CreateTask(t.Context(), task)
 
-	err := taskService.DeleteById(createdTask.Id)
+	err := taskService.DeleteById(t.Context(), createdTask.Id)
 	assert.NoError(t, err)
 
-	_, err = taskService.GetTaskByID(createdTask.Id)
+	_, err = taskService.GetTaskByID(t.Context(), createdTask.Id)
 	assert.Error(t, err)
 
-	err = taskService.DeleteById(999)
+	err = taskService.DeleteById(t.Context(), 999)
 	assert.Error(t, err)
 }
+
+func TestTaskService_Versions(t *testing.T) {
+	taskService := NewTaskService(repository.NewMockTaskRepository())
+
+	created, err := taskService.CreateTask(t.Context(), model.Task{Name: "Task"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), created.Version)
+
+	updated, err := taskService.UpdateTask(t.Context(), created.Id, model.Task{Name: "Renamed", Version: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), updated.Version)
+
+	_, err = taskService.UpdateTask(t.Context(), created.Id, model.Task{Name: "Stale", Version: 1})
+	assert.ErrorIs(t, err, ErrVersionMismatch)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+
+	updated, err = taskService.UpdateTask(t.Context(), created.Id, model.Task{Name: "Unconditional"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), updated.Version)
+
+	assert.ErrorIs(t, taskService.DeleteVersion(t.Context(), created.Id, 2), ErrVersionMismatch)
+	assert.NoError(t, taskService.DeleteVersion(t.Context(), created.Id, 3))
+	assert.ErrorIs(t, taskService.DeleteVersion(t.Context(), created.Id, 3), ErrNotFound)
+}
+
+func TestTaskService_FindTasks(t *testing.T) {
+	mockRepo := repository.NewMockTaskRepository()
+	taskService := NewTaskService(mockRepo)
+
+	now := time.Now()
+	mockRepo.CreateTask(t.Context(), model.Task{Name: "Write report", Status: model.StatusNew, Date: now.Add(-3 * time.Hour)})
+	mockRepo.CreateTask(t.Context(), model.Task{Name: "Review report", Status: model.StatusDone, Date: now.Add(-2 * time.Hour)})
+	mockRepo.CreateTask(t.Context(), model.Task{Name: "Deploy", Status: model.StatusNew, Date: now.Add(-time.Hour)})
+
+	page, err := taskService.FindTasks(t.Context(), model.TaskQuery{Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, DefaultPageSize, page.Limit)
+
+	page, err = taskService.FindTasks(t.Context(), model.TaskQuery{NameContains: "REPORT", SortBy: model.SortByDate, SortDesc: true})
+	assert.NoError(t, err)
+	assert.Len(t, page.Tasks, 2)
+	assert.Equal(t, "Review report", page.Tasks[0].Name)
+
+	after := now.Add(-150 * time.Minute)
+	page, err = taskService.FindTasks(t.Context(), model.TaskQuery{CreatedAfter: &after, Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Len(t, page.Tasks, 1)
+	assert.Equal(t, "Deploy", page.Tasks[0].Name)
+
+	_, err = taskService.FindTasks(t.Context(), model.TaskQuery{SortBy: "unknown"})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+
+	_, err = taskService.FindTasks(t.Context(), model.TaskQuery{Offset: -1})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestTaskService_FindTasksWithCursor(t *testing.T) {
+	mockRepo := repository.NewMockTaskRepository()
+	taskService := NewTaskService(mockRepo)
+
+	date := time.Now()
+	for i := 0; i < 5; i++ {
+		// two tasks share every date so that ties are resolved by id
+		mockRepo.CreateTask(t.Context(), model.Task{Name: "Task", Status: model.StatusNew, Date: date.Add(time.Duration(i/2) * time.Minute)})
+	}
+
+	var seen []uint
+	query := model.TaskQuery{SortBy: model.SortByDate, Limit: 2}
+	for {
+		page, err := taskService.FindTasks(t.Context(), query)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), page.Total)
+		for _, task := range page.Tasks {
+			seen = append(seen, task.Id)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursor, err := model.DecodeTaskCursor(page.NextCursor.Encode())
+		assert.NoError(t, err)
+		query.After = &cursor
+	}
+	assert.Equal(t, []uint{1, 2, 3, 4, 5}, seen)
+
+	_, err := taskService.FindTasks(t.Context(), model.TaskQuery{SortBy: model.SortByName, After: &model.TaskCursor{Id: 1}})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+
+	_, err = taskService.FindTasks(t.Context(), model.TaskQuery{Offset: 2, After: &model.TaskCursor{Id: 1}})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+
+	_, err = model.DecodeTaskCursor("not a cursor")
+	assert.ErrorIs(t, err, model.ErrInvalidCursor)
+}
+
+func TestTaskService_TaskFields(t *testing.T) {
+	mockRepo := repository.NewMockTaskRepository()
+	taskService := NewTaskService(mockRepo)
+
+	due := time.Now().Add(48 * time.Hour)
+	createdTask, err := taskService.CreateTask(t.Context(), model.Task{Name: "Release", Description: "Ship 1.0", Assignee: "alex", DueDate: &due})
+	assert.NoError(t, err)
+	assert.Equal(t, model.PriorityMedium, createdTask.Priority)
+	assert.False(t, createdTask.CreatedAt.IsZero())
+
+	updated, err := taskService.UpdateTask(t.Context(), createdTask.Id, model.Task{Name: "Release", Priority: model.PriorityCritical, Assignee: "kim", DueDate: &due})
+	assert.NoError(t, err)
+	assert.Equal(t, model.PriorityCritical, updated.Priority)
+	assert.Equal(t, "kim", updated.Assignee)
+	assert.Equal(t, createdTask.CreatedAt, updated.CreatedAt)
+
+	past := time.Now().Add(-48 * time.Hour)
+	_, err = taskService.CreateTask(t.Context(), model.Task{Name: "Late", Date: time.Now(), DueDate: &past})
+	assert.ErrorIs(t, err, ErrInvalidTask)
+
+	assert.NoError(t, validateTask(model.Task{Name: strings.Repeat("я", MaxNameLength), Priority: model.PriorityMedium}))
+	assert.ErrorIs(t, validateTask(model.Task{Name: strings.Repeat("я", MaxNameLength+1), Priority: model.PriorityMedium}), ErrInvalidTask)
+
+	_, err = taskService.CreateTask(t.Context(), model.Task{Name: "Odd", Priority: 7})
+	assert.ErrorIs(t, err, model.ErrUnknownPriority)
+
+	taskService.CreateTask(t.Context(), model.Task{Name: "Chore", Priority: model.PriorityLow})
+	page, err := taskService.FindTasks(t.Context(), model.TaskQuery{SortBy: model.SortByPriority, SortDesc: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "Release", page.Tasks[0].Name)
+	assert.Equal(t, "Chore", page.Tasks[1].Name)
+
+	page, err = taskService.FindTasks(t.Context(), model.TaskQuery{SortBy: model.SortByDueDate, SortDesc: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "Release", page.Tasks[0].Name)
+
+	before := time.Now().Add(72 * time.Hour)
+	page, err = taskService.FindTasks(t.Context(), model.TaskQuery{DueBefore: &before, Assignee: "kim"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+}
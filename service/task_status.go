@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"task_manager_go/model"
+)
+
+// ErrInvalidTransition is returned when a task cannot move from its current status to the requested one.
+var ErrInvalidTransition = model.NewError(model.ErrConflict, "invalid status transition")
+
+// transitions lists the statuses a task may move to from each status.
+// Done and Cancelled tasks can only be reopened.
+var transitions = map[model.Status][]model.Status{
+	model.StatusNew:        {model.StatusInProgress, model.StatusBlocked, model.StatusDone, model.StatusCancelled},
+	model.StatusInProgress: {model.StatusNew, model.StatusBlocked, model.StatusDone, model.StatusCancelled},
+	model.StatusBlocked:    {model.StatusNew, model.StatusInProgress, model.StatusCancelled},
+	model.StatusDone:       {model.StatusInProgress},
+	model.StatusCancelled:  {model.StatusNew},
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Keeping the current status is always allowed.
+func CanTransition(from, to model.Status) bool {
+	return from == to || slices.Contains(transitions[from], to)
+}
+
+// checkTransition returns ErrInvalidTransition when a task may not move from one status to another.
+func checkTransition(from, to model.Status) error {
+	if !to.IsValid() {
+		return fmt.Errorf("%w %q", model.ErrUnknownStatus, to)
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/json"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, CanTransition(model.StatusNew, model.StatusInProgress))
+	assert.True(t, CanTransition(model.StatusInProgress, model.StatusDone))
+	assert.True(t, CanTransition(model.StatusDone, model.StatusDone))
+	assert.True(t, CanTransition(model.StatusCancelled, model.StatusNew))
+	assert.False(t, CanTransition(model.StatusDone, model.StatusCancelled))
+	assert.False(t, CanTransition(model.StatusBlocked, model.StatusDone))
+	assert.False(t, CanTransition(model.StatusCancelled, model.StatusInProgress))
+}
+
+func TestTaskService_UpdateTaskTransitions(t *testing.T) {
+	mockRepo := repository.NewMockTaskRepository()
+	taskService := NewTaskService(mockRepo)
+
+	createdTask, err := taskService.CreateTask(t.Context(), model.Task{Name: "Task"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusNew, createdTask.Status)
+
+	result, err := taskService.UpdateTask(t.Context(), createdTask.Id, model.Task{Name: "Renamed"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusNew, result.Status)
+
+	_, err = taskService.UpdateTask(t.Context(), createdTask.Id, model.Task{Name: "Task", Status: model.StatusBlocked})
+	assert.NoError(t, err)
+
+	_, err = taskService.UpdateTask(t.Context(), createdTask.Id, model.Task{Name: "Task", Status: model.StatusDone})
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	_, err = taskService.UpdateTask(t.Context(), createdTask.Id, model.Task{Name: "Task", Status: "Pending"})
+	assert.ErrorIs(t, err, model.ErrUnknownStatus)
+
+	_, err = taskService.CreateTask(t.Context(), model.Task{Name: "Task", Status: "pending"})
+	assert.ErrorIs(t, err, model.ErrUnknownStatus)
+}
+
+func TestStatusJSON(t *testing.T) {
+	var task model.Task
+	assert.NoError(t, json.Unmarshal([]byte(`{"Name":"Task","Status":"InProgress"}`), &task))
+	assert.Equal(t, model.StatusInProgress, task.Status)
+
+	err := json.Unmarshal([]byte(`{"Name":"Task","Status":"Completed"}`), &task)
+	assert.ErrorIs(t, err, model.ErrUnknownStatus)
+
+	status, ok := model.NormalizeStatus("In Progress")
+	assert.True(t, ok)
+	assert.Equal(t, model.StatusInProgress, status)
+}
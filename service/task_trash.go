@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"task_manager_go/model"
+	"time"
+)
+
+// ErrParentDeleted is returned when a task is restored while its parent is still in the trash.
+var ErrParentDeleted = model.NewError(model.ErrConflict, "parent task is in the trash")
+
+// GetTrash returns the deleted tasks that have not been purged yet, most recently deleted first.
+func (t *TaskService) GetTrash(ctx context.Context) (tasks []model.Task, err error) {
+	defer t.observe("get_trash", &err)
+	return t.repo.FindDeleted(ctx)
+}
+
+// RestoreTask takes a deleted task out of the trash together with its comments, checklist, tags
+// and dependencies, which block again as they did before the task was deleted.
+// Subtasks deleted with it stay in the trash and are restored one by one, parents first.
+// Returns the restored task and an error if the task is not in the trash, its parent is or another error occurred.
+func (t *TaskService) RestoreTask(ctx context.Context, id uint) (restored model.Task, err error) {
+	defer t.observe("restore_task", &err)
+	err = t.inTransaction(ctx, func(tx *TaskService) error {
+		restored, err = tx.restoreTask(ctx, id)
+		return err
+	})
+	return restored, err
+}
+
+// restoreTask takes a task out of the trash and records it in the audit trail.
+func (t *TaskService) restoreTask(ctx context.Context, id uint) (model.Task, error) {
+	task, err := t.repo.FindDeletedById(ctx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if task.ParentId != nil {
+		if _, err := t.repo.FindById(ctx, *task.ParentId); errors.Is(err, ErrNotFound) {
+			return model.Task{}, fmt.Errorf("%w: restore task %d first", ErrParentDeleted, *task.ParentId)
+		} else if err != nil {
+			return model.Task{}, err
+		}
+	}
+	restored, err := t.repo.Restore(ctx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return restored, t.record(ctx, model.AuditRestore, id, nil, &restored)
+}
+
+// PurgeTrash permanently removes the tasks deleted before the given moment with their comments, checklists and dependencies.
+// Returns the number of purged tasks and the first error that stopped the purge.
+func (t *TaskService) PurgeTrash(ctx context.Context, deletedBefore time.Time) (purged int, err error) {
+	defer t.observe("purge_trash", &err)
+	tasks, err := t.repo.FindDeleted(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, task := range tasks {
+		if !task.DeletedAt.Time.Before(deletedBefore) {
+			continue
+		}
+		if err := t.purgeTask(ctx, task.Id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// RunTrashPurge purges the tasks that have been in the trash longer than retention every interval until ctx is done.
+// Failed purges are logged and retried at the next interval.
+func (t *TaskService) RunTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := t.PurgeTrash(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Println("Failed to purge the trash:", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d tasks from the trash", purged)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeTask permanently removes a deleted task with its comments, checklist and dependencies.
+func (t *TaskService) purgeTask(ctx context.Context, id uint) error {
+	return t.inTransaction(ctx, func(tx *TaskService) error {
+		if tx.deps != nil {
+			if err := tx.deps.DeleteByTask(ctx, id); err != nil {
+				return err
+			}
+		}
+		if tx.comments != nil {
+			if err := tx.comments.DeleteByTask(ctx, id); err != nil {
+				return err
+			}
+		}
+		if tx.checklists != nil {
+			if err := tx.checklists.DeleteByTask(ctx, id); err != nil {
+				return err
+			}
+		}
+		if err := tx.repo.Purge(ctx, id); err != nil {
+			return err
+		}
+		return tx.record(ctx, model.AuditPurge, id, nil, nil)
+	})
+}
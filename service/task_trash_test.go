@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"task_manager_go/model"
+	"task_manager_go/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskService_Trash(t *testing.T) {
+	taskService := NewTaskService(repository.NewMockTaskRepository(), WithChildDeletePolicy(DeleteCascade))
+
+	parent := createSubtask(t, taskService, "Parent", nil)
+	child := createSubtask(t, taskService, "Child", &parent)
+	other := createSubtask(t, taskService, "Other", nil)
+
+	assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+	_, err := taskService.GetTaskByID(t.Context(), child.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+	page, err := taskService.FindTasks(t.Context(), model.TaskQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Task{other}, page.Tasks)
+	assert.ErrorIs(t, taskService.DeleteById(t.Context(), parent.Id), ErrNotFound)
+
+	trash, err := taskService.GetTrash(t.Context())
+	assert.NoError(t, err)
+	assert.Len(t, trash, 2)
+	for _, task := range trash {
+		assert.True(t, task.DeletedAt.Valid)
+	}
+
+	_, err = taskService.RestoreTask(t.Context(), child.Id)
+	assert.ErrorIs(t, err, ErrParentDeleted)
+	_, err = taskService.RestoreTask(t.Context(), other.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	restored, err := taskService.RestoreTask(t.Context(), parent.Id)
+	assert.NoError(t, err)
+	assert.False(t, restored.DeletedAt.Valid)
+	assert.Equal(t, parent.Version+1, restored.Version)
+	restored, err = taskService.RestoreTask(t.Context(), child.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, &parent.Id, restored.ParentId)
+
+	subtasks, err := taskService.GetSubtasks(t.Context(), parent.Id)
+	assert.NoError(t, err)
+	assert.Len(t, subtasks, 1)
+}
+
+func TestTaskService_PurgeTrash(t *testing.T) {
+	taskService := NewTaskService(repository.NewMockTaskRepository())
+
+	parent := createSubtask(t, taskService, "Parent", nil)
+	child := createSubtask(t, taskService, "Child", &parent)
+	assert.NoError(t, taskService.DeleteById(t.Context(), parent.Id))
+
+	purged, err := taskService.PurgeTrash(t.Context(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, purged)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	taskService.RunTrashPurge(ctx, 0, time.Hour)
+
+	trash, err := taskService.GetTrash(t.Context())
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+	_, err = taskService.RestoreTask(t.Context(), parent.Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	orphan, err := taskService.GetTaskByID(t.Context(), child.Id)
+	assert.NoError(t, err)
+	assert.Nil(t, orphan.ParentId)
+	next := createSubtask(t, taskService, "Next", nil)
+	assert.NotEqual(t, parent.Id, next.Id)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"log"
+	"task_manager_go/config"
+	"task_manager_go/controller"
+	"task_manager_go/metrics"
+	repository2 "task_manager_go/repository"
+
+	"gorm.io/gorm"
+)
+
+// repositories bundles the repositories the services of the server are built on.
+type repositories struct {
+	repository2.Repositories
+	// Events gives access to the event log of tasks, nil unless tasks are stored as events
+	Events repository2.TaskEventRepositoryInterface
+	// Transactor runs changes of several repositories in one transaction, nil if the store has no transactions
+	Transactor repository2.Transactor
+	// Checks must pass for the server to take requests, empty if the store has no dependencies
+	Checks []controller.HealthChecker
+	// close releases the database connections or files of the store, nil if there are none
+	close func() error
+}
+
+// Close releases the database connections or files of the store.
+// The repositories must not be used afterwards.
+func (r repositories) Close() error {
+	if r.close == nil {
+		return nil
+	}
+	return r.close()
+}
+
+// openRepositories creates the repositories of the store selected by cfg.
+// The database and events stores keep everything in the configured database,
+// the memory store keeps everything in memory and the file store keeps everything in a local file,
+// both without a database. Background work of the store is run by workers
+// and the metrics of the database, if any, are recorded in registry.
+// Exits when the store cannot be opened.
+func openRepositories(workers *workers, registry *metrics.Registry, cfg config.Config) repositories {
+	switch cfg.Store.Kind {
+	case config.StoreEvents:
+		return databaseRepositories(cfg, registry, func(db *gorm.DB) repository2.TaskRepositoryInterface {
+			return repository2.NewTaskEventRepository(db)
+		}, repository2.NewTaskEventRepository)
+	case config.StoreMemory:
+		return memoryRepositories(workers, cfg.Store)
+	case config.StoreFile:
+		return fileRepositories(cfg.Store)
+	default:
+		return databaseRepositories(cfg, registry, repository2.NewTaskRepository, nil)
+	}
+}
+
+// databaseRepositories connects to the database, records its metrics in registry and creates its repositories.
+// The schema is checked once here, so that the readiness probe only needs to ping the database.
+// newTasks creates the task repository and newEvents, if not nil, the repository of the event log.
+func databaseRepositories(
+	cfg config.Config,
+	registry *metrics.Registry,
+	newTasks func(db *gorm.DB) repository2.TaskRepositoryInterface,
+	newEvents func(db *gorm.DB) repository2.TaskEventRepositoryInterface,
+) repositories {
+	db := config.InitDB(cfg)
+	sqlDb, err := db.DB()
+	if err != nil {
+		log.Fatalf("creating db is uncompleted %v", err)
+	}
+	if err := config.InstrumentDB(db, registry); err != nil {
+		log.Fatalf("instrumenting the database: %v", err)
+	}
+	if err := config.CheckMigrations(context.Background(), db); err != nil {
+		log.Fatalf("checking the database schema: %v", err)
+	}
+	repos := repositories{
+		Repositories: repository2.Repositories{
+			Tasks:        newTasks(db),
+			Tags:         repository2.NewTagRepository(db),
+			Audit:        repository2.NewAuditRepository(db),
+			Dependencies: repository2.NewDependencyRepository(db),
+			Comments:     repository2.NewCommentRepository(db),
+			Checklists:   repository2.NewChecklistRepository(db),
+		},
+		Transactor: repository2.NewTransactor(db, newTasks),
+		Checks: []controller.HealthChecker{
+			controller.HealthCheck("database", sqlDb.PingContext),
+		},
+		close: sqlDb.Close,
+	}
+	if newEvents != nil {
+		repos.Events = newEvents(db)
+	}
+	return repos
+}
+
+// memoryRepositories creates repositories that keep everything in memory.
+// When a snapshot file is configured, the tasks are loaded from it and saved to it
+// every snapshot interval in which they changed, and once more when workers stop.
+// Everything else starts empty on every start. Changes of several repositories run in transactions of the store.
+func memoryRepositories(workers *workers, cfg config.StoreConfig) repositories {
+	tasks := repository2.NewMemoryTaskRepository()
+	if path := cfg.MemorySnapshot; path != "" {
+		var err error
+		if tasks, err = repository2.LoadMemoryTaskRepository(path); err != nil {
+			log.Fatalf("loading the task snapshot %s: %v", path, err)
+		}
+		workers.Go(func(ctx context.Context) {
+			tasks.RunSnapshots(ctx, path, cfg.MemorySnapshotInterval)
+		})
+	}
+	store := repository2.NewMemoryStore(tasks)
+	return repositories{
+		Repositories: store.Repositories(),
+		Transactor:   store,
+	}
+}
+
+// fileRepositories opens the configured file, which keeps tasks with their tags, comments, checklists,
+// dependencies and audit trail. Every change is written to the file before it is answered,
+// the changes of a transaction all at once.
+func fileRepositories(cfg config.StoreConfig) repositories {
+	store, err := repository2.OpenFileStore(cfg.File)
+	if err != nil {
+		log.Fatalf("opening the task file %s: %v", cfg.File, err)
+	}
+	return repositories{
+		Repositories: store.Repositories(),
+		Transactor:   store,
+		close:        store.Close,
+	}
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func setupTestEnvironment(t *testing.T) (*service.TaskService, func()) {
-	db, cleanup := config.InitTestDBWithDocker()
+	db, cleanup := config.InitTestDB()
 	repo := repository.NewTaskRepository(db)
 	taskService := service.NewTaskService(repo)
 	return taskService, cleanup
@@ -21,10 +21,10 @@ func setupTestEnvironment(t *testing.T) (*service.TaskService, func()) {
 func createTestTask(t *testing.T, service *service.TaskService) model.Task {
 	task := model.Task{
 		Name:   "Test Task",
-		Status: "Pending",
+		Status: model.StatusNew,
 		Date:   time.Now().Truncate(time.Millisecond),
 	}
-	createdTask, err := service.CreateTask(task)
+	createdTask, err := service.CreateTask(t.Context(), task)
 	assert.NoError(t, err)
 	assert.NotNil(t, createdTask)
 	return createdTask
@@ -38,10 +38,10 @@ func TestTaskCRUDIntegration(t *testing.T) {
 	t.Run("Create Task", func(t *testing.T) {
 		task := model.Task{
 			Name:   "Integration Test Task",
-			Status: "New",
+			Status: model.StatusNew,
 			Date:   time.Now().Truncate(time.Millisecond),
 		}
-		createdTask, err := service.CreateTask(task)
+		createdTask, err := service.CreateTask(t.Context(), task)
 		assert.NoError(t, err)
 		assert.NotNil(t, createdTask)
 		assert.NotZero(t, createdTask.Id)
@@ -54,13 +54,13 @@ func TestTaskCRUDIntegration(t *testing.T) {
 		createdTask := createTestTask(t, service)
 
 		// Test Get by ID
-		foundTask, err := service.GetTaskByID(createdTask.Id)
+		foundTask, err := service.GetTaskByID(t.Context(), createdTask.Id)
 		assert.NoError(t, err)
 		assert.Equal(t, createdTask.Id, foundTask.Id)
 		assert.Equal(t, createdTask.Name, foundTask.Name)
 
 		// Test Get All
-		allTasks, err := service.GetAllTasks()
+		allTasks, err := service.GetAllTasks(t.Context())
 		assert.NoError(t, err)
 		assert.NotEmpty(t, allTasks)
 	})
@@ -71,11 +71,11 @@ func TestTaskCRUDIntegration(t *testing.T) {
 
 		updatedTask := model.Task{
 			Name:   "Updated Task",
-			Status: "Completed",
+			Status: model.StatusDone,
 			Date:   time.Now().Truncate(time.Millisecond),
 		}
 
-		result, err := service.UpdateTask(createdTask.Id, updatedTask)
+		result, err := service.UpdateTask(t.Context(), createdTask.Id, updatedTask)
 		assert.NoError(t, err)
 		assert.Equal(t, updatedTask.Name, result.Name)
 		assert.Equal(t, updatedTask.Status, result.Status)
@@ -86,11 +86,11 @@ func TestTaskCRUDIntegration(t *testing.T) {
 		createdTask := createTestTask(t, service)
 
 		// Test successful deletion
-		err := service.DeleteById(createdTask.Id)
+		err := service.DeleteById(t.Context(), createdTask.Id)
 		assert.NoError(t, err)
 
 		// Verify task is deleted
-		_, err = service.GetTaskByID(createdTask.Id)
+		_, err = service.GetTaskByID(t.Context(), createdTask.Id)
 		assert.Error(t, err)
 	})
 }
@@ -100,21 +100,21 @@ func TestTaskErrorCases(t *testing.T) {
 	defer cleanup()
 
 	t.Run("Get Non-existent Task", func(t *testing.T) {
-		_, err := service.GetTaskByID(999)
+		_, err := service.GetTaskByID(t.Context(), 999)
 		assert.Error(t, err)
 	})
 
 	t.Run("Update Non-existent Task", func(t *testing.T) {
 		nonExistentTask := model.Task{
 			Name:   "Non-existent",
-			Status: "Unknown",
+			Status: model.StatusDone,
 		}
-		_, err := service.UpdateTask(999, nonExistentTask)
+		_, err := service.UpdateTask(t.Context(), 999, nonExistentTask)
 		assert.Error(t, err)
 	})
 
 	t.Run("Delete Non-existent Task", func(t *testing.T) {
-		err := service.DeleteById(999)
+		err := service.DeleteById(t.Context(), 999)
 		assert.Error(t, err)
 	})
 }
@@ -128,7 +128,7 @@ func TestTaskConcurrentOperations(t *testing.T) {
 	for i := range tasks {
 		tasks[i] = model.Task{
 			Name:   "Concurrent Task",
-			Status: "Pending",
+			Status: model.StatusNew,
 			Date:   time.Now().Truncate(time.Millisecond),
 		}
 	}
@@ -137,8 +137,8 @@ func TestTaskConcurrentOperations(t *testing.T) {
 	t.Run("Concurrent Creation", func(t *testing.T) {
 		results := make(chan error, len(tasks))
 		for _, task := range tasks {
-			go func(t model.Task) {
-				_, err := service.CreateTask(t)
+			go func(task model.Task) {
+				_, err := service.CreateTask(t.Context(), task)
 				results <- err
 			}(task)
 		}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"task_manager_go/model"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError describes why the value of a single field was rejected.
+type FieldError struct {
+	// Field is the name of the field as it appears in the JSON payload
+	Field string `json:"field"`
+	// Message describes what is wrong with the value
+	Message string `json:"message"`
+}
+
+// Errors lists the invalid fields of a payload.
+// It is an error of the model.ErrValidation kind.
+type Errors []FieldError
+
+// Error returns the field errors joined into a single message.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Field + ": " + fieldError.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether target is model.ErrValidation.
+func (e Errors) Is(target error) bool {
+	return target == model.ErrValidation
+}
+
+// Validator collects the field errors of a payload.
+// The zero value is ready to use.
+type Validator struct {
+	errors Errors
+}
+
+// Check records message for field unless ok holds.
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.errors = append(v.errors, FieldError{Field: field, Message: message})
+	}
+}
+
+// Required checks that value is not blank.
+func (v *Validator) Required(field, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+// MaxLength checks that value has at most max characters.
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, fmt.Sprintf("must be at most %d characters long", max))
+}
+
+// OneOf checks that value is one of allowed. An empty value is accepted, combine with Required to reject it.
+func (v *Validator) OneOf(field, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.Check(false, field, "must be one of "+strings.Join(allowed, ", "))
+}
+
+// NotBefore checks that value, if present, is not before min. minName describes min in the message.
+func (v *Validator) NotBefore(field string, value *time.Time, min time.Time, minName string) {
+	if value != nil {
+		v.Check(!value.Before(min), field, "must not be before "+minName)
+	}
+}
+
+// Valid reports whether no field error was recorded.
+func (v *Validator) Valid() bool {
+	return len(v.errors) == 0
+}
+
+// Err returns the recorded field errors as Errors, or nil when every field is valid.
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return v.errors
+}
@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"task_manager_go/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	var v Validator
+	assert.True(t, v.Valid())
+	assert.NoError(t, v.Err())
+
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	v.Required("Name", "  ")
+	v.MaxLength("Assignee", "alexander", 4)
+	v.MaxLength("Description", "задача", 6)
+	v.OneOf("Status", "Pending", []string{"New", "Done"})
+	v.OneOf("Priority", "", []string{"Low"})
+	v.NotBefore("DueDate", &past, now, "the current time")
+	v.NotBefore("StartDate", nil, now, "the current time")
+
+	err := v.Err()
+	assert.ErrorIs(t, err, model.ErrValidation)
+	assert.Equal(t, Errors{
+		{Field: "Name", Message: "is required"},
+		{Field: "Assignee", Message: "must be at most 4 characters long"},
+		{Field: "Status", Message: "must be one of New, Done"},
+		{Field: "DueDate", Message: "must not be before the current time"},
+	}, err)
+
+	v = Validator{}
+	v.MaxLength("Description", "задачи", 5)
+	assert.Equal(t, Errors{{Field: "Description", Message: "must be at most 5 characters long"}}, v.Err())
+	assert.Equal(t, "Name: is required; Assignee: must be at most 4 characters long; "+
+		"Status: must be one of New, Done; DueDate: must not be before the current time", err.Error())
+}